@@ -0,0 +1,13 @@
+// Command synchandler syncs TeamUnify news and calendar content into the
+// website. Run "synchandler sync --only=news" to limit it to one pipeline.
+package main
+
+import (
+	"os"
+
+	"github.com/dareaquatics/dare-website/internal/app"
+)
+
+func main() {
+	app.Main(os.Args[1:])
+}
@@ -0,0 +1,786 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/changelog"
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/dareaquatics/dare-website/internal/hooks"
+	"github.com/dareaquatics/dare-website/internal/notify"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/search"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+	"github.com/dareaquatics/dare-website/internal/site"
+	"github.com/dareaquatics/dare-website/internal/state"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+const commitPrefix = "automated commit: sync TeamUnify "
+
+// stateCommit is the message of commits that carry only the state file.
+// It does not start with commitPrefix, so rollback passes over them.
+const stateCommit = "automated commit: update sync state [skip ci]"
+
+// pipeline is one content source (news, calendar) that renders into the
+// marker block of a single HTML file.
+type pipeline interface {
+	Name() string
+	File() string
+	// Subject names the content in commit messages, e.g. "news articles".
+	Subject() string
+	// Noun names a single item in the changelog, e.g. "article".
+	Noun() string
+	// Build fetches and renders the block. A nil output means there is
+	// nothing worth publishing this run.
+	Build(ctx context.Context, rep *report.Pipeline) (*output, error)
+	// Publish pushes to any destinations beyond the git repository. rep
+	// carries this run's changes once the pages were written.
+	Publish(ctx context.Context, rep *report.Pipeline) error
+}
+
+// output is what a pipeline rendered this run.
+type output struct {
+	block string
+	// items describes the rendered articles or events for the state file
+	// and changelog.
+	items []state.Item
+	// extras are further pages the pipeline fills, such as the news
+	// archive. Their items are kept in state under their own name.
+	extras []page
+	// restyle renders the main block again in another style for
+	// render.mirrors.
+	restyle func(style) string
+	// records is everything fetched this run, archived content included,
+	// for the export command.
+	records []record
+	// dataOnly leaves the pipeline's page alone; a primary data file
+	// stands in for its block.
+	dataOnly bool
+}
+
+// style is how a page is rendered: its markup profile and the languages
+// its block is written in.
+type style struct {
+	profile   *render.Profile
+	languages []*render.Catalog
+}
+
+type page struct {
+	name  string
+	file  string
+	block string
+	items []state.Item
+	// standalone pages are generated whole instead of between markers.
+	standalone bool
+	// primary pages count as the pipeline's own output, so their changes
+	// are recorded in state and the changelog.
+	primary bool
+}
+
+func (pg page) write() (bool, error) {
+	if pg.standalone {
+		return site.Write(pg.file, pg.block)
+	}
+	return site.Patch(pg.file, pg.block)
+}
+
+// current returns what write would replace.
+func (pg page) current() (string, error) {
+	html, err := os.ReadFile(pg.file)
+	if pg.standalone && errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pg.file, err)
+	}
+	if pg.standalone {
+		return string(html), nil
+	}
+
+	block, err := site.Block(string(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pg.file, err)
+	}
+	return block, nil
+}
+
+// App holds what every subcommand shares.
+type App struct {
+	cfg      *config.Config
+	log      *logrus.Logger
+	client   *http.Client
+	api      *http.Client
+	notifier *notify.Notifier
+	offline  bool
+	// cacheDir holds responses and article bodies between runs; empty
+	// when caching is off.
+	cacheDir string
+	// retryChanged is set when the news retry queue in state changed this
+	// run. The state file is then committed even when no page changed, so
+	// the queue survives CI's fresh checkouts.
+	retryChanged bool
+	// state is what the last sync published, loaded before pipelines run.
+	state *state.State
+	// styles holds the render style for pages configured by path;
+	// defaultStyle covers the rest.
+	styles       map[string]style
+	defaultStyle style
+	// policy is the content.policy setting, shared by the transformer
+	// pipeline and the renderers.
+	policy *content.Policy
+}
+
+var commands = map[string]func(args []string){
+	"sync":     runSync,
+	"diff":     runDiff,
+	"rollback": runRollback,
+	"export":   runExport,
+	"import":   runImport,
+}
+
+// Main dispatches to a subcommand; with no subcommand it runs sync.
+func Main(args []string) {
+	name := "sync"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\nusage: synchandler [sync|diff|rollback|export|import] [flags]\n", name)
+		os.Exit(2)
+	}
+	cmd(args)
+}
+
+func setupLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetFormatter(&secrets.Formatter{Formatter: &logrus.TextFormatter{
+		ForceColors:   true,
+		FullTimestamp: true,
+	}})
+	log.SetLevel(logrus.InfoLevel)
+	return log
+}
+
+// commonFlags registers the flags shared by commands that fetch content.
+type commonFlags struct {
+	only     *string
+	offline  *bool
+	fixtures *string
+	root     *string
+	pprof    *string
+	noCache  *bool
+}
+
+func addCommonFlags(fs *flag.FlagSet) commonFlags {
+	return commonFlags{
+		only:     fs.String("only", "news,calendar", "comma-separated pipelines to run"),
+		offline:  fs.Bool("offline", false, "run against saved fixtures and write output locally without git"),
+		fixtures: fs.String("fixtures", "", "fixture directory for --offline"),
+		root:     fs.String("root", "", `website repository root (default "../../", or "." with --offline)`),
+		pprof:    fs.String("pprof-addr", "", "serve net/http/pprof on this address, e.g. localhost:6060"),
+		noCache:  fs.Bool("no-cache", false, "fetch everything from TeamUnify, ignoring the response cache"),
+	}
+}
+
+// setup loads config, builds clients and pipelines and changes into the
+// website repository root.
+func setup(log *logrus.Logger, flags commonFlags) (*App, []pipeline) {
+	if *flags.pprof != "" {
+		startPprof(*flags.pprof, log)
+	}
+
+	if *flags.offline && *flags.fixtures == "" {
+		log.Fatal("--offline requires --fixtures")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	transport := fetch.NewTransport(cfg.Fetch)
+	a := &App{
+		cfg:     cfg,
+		log:     log,
+		api:     &http.Client{Transport: transport},
+		offline: *flags.offline,
+	}
+	a.notifier = notify.New(cfg.Notify.WebhookURL, cfg.Notify.Format, *flags.only, a.api)
+	if a.policy, err = contentPolicy(cfg.Content.Policy); err != nil {
+		log.Fatalf("failed to load content policy: %v", err)
+	}
+	if err := a.loadStyles(); err != nil {
+		log.Fatalf("failed to load render settings: %v", err)
+	}
+
+	root := *flags.root
+	if a.offline {
+		fixtures, err := filepath.Abs(*flags.fixtures)
+		if err != nil {
+			log.Fatalf("failed to resolve fixtures: %v", err)
+		}
+		log.Infof("offline mode: serving requests from %s", fixtures)
+		a.client = &http.Client{Transport: &fetch.Offline{Dir: fixtures}}
+		if root == "" {
+			root = "."
+		}
+	} else {
+		if !*flags.noCache {
+			a.cacheDir = cfg.Fetch.CacheDir
+		}
+		a.client, err = fetch.NewClient(cfg.Fetch, transport, baseURL, a.cacheDir)
+		if err != nil {
+			log.Fatalf("failed to set up http client: %v", err)
+		}
+		if root == "" {
+			root = "../../"
+		}
+	}
+
+	var pipelines []pipeline
+	var weekly bool
+	for _, name := range strings.Split(*flags.only, ",") {
+		switch strings.TrimSpace(name) {
+		case "news":
+			p, err := a.newNewsPipeline()
+			if err != nil {
+				log.Fatalf("failed to set up news pipeline: %v", err)
+			}
+			pipelines = append(pipelines, p)
+		case "calendar":
+			p, err := a.newCalendarPipeline()
+			if err != nil {
+				log.Fatalf("failed to set up calendar pipeline: %v", err)
+			}
+			pipelines = append(pipelines, p)
+		case "registration":
+			pipelines = append(pipelines, a.newRegistrationPipeline())
+		case "schedule":
+			p, err := a.newSchedulePipeline()
+			if err != nil {
+				log.Fatalf("failed to set up schedule pipeline: %v", err)
+			}
+			pipelines = append(pipelines, p)
+		case "weekly":
+			weekly = true
+		default:
+			log.Fatalf("unknown pipeline %q", name)
+		}
+	}
+	if weekly {
+		var news *newsPipeline
+		var cal *calendarPipeline
+		for _, p := range pipelines {
+			switch p := p.(type) {
+			case *newsPipeline:
+				news = p
+			case *calendarPipeline:
+				cal = p
+			}
+		}
+		if news == nil || cal == nil {
+			log.Fatal("the weekly pipeline needs news and calendar in the same run, e.g. --only=news,calendar,weekly")
+		}
+		pipelines = append(pipelines, a.newWeeklyPipeline(news, cal))
+	}
+
+	// Change working directory to repository root
+	if err := os.Chdir(root); err != nil {
+		log.Fatalf("failed to change directory: %v", err)
+	}
+
+	a.state, err = state.Load(a.cfg.StateFile)
+	if err != nil {
+		log.Fatalf("failed to load state: %v", err)
+	}
+	return a, pipelines
+}
+
+// loadStyles resolves every profile and language named in the config up
+// front so a typo fails the run before anything is fetched.
+func (a *App) loadStyles() error {
+	var err error
+	if a.defaultStyle, err = a.newStyle(a.cfg.Render.Profile, a.cfg.Render.Languages); err != nil {
+		return err
+	}
+
+	a.styles = map[string]style{}
+	for file, name := range a.cfg.Render.Files {
+		if a.styles[file], err = a.newStyle(name, a.cfg.Render.Languages); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	for _, m := range a.cfg.Render.Mirrors {
+		languages := m.Languages
+		if languages == nil {
+			languages = a.cfg.Render.Languages
+		}
+		if a.styles[m.File], err = a.newStyle(m.Profile, languages); err != nil {
+			return fmt.Errorf("%s: %w", m.File, err)
+		}
+	}
+	return nil
+}
+
+func (a *App) newStyle(profile string, languages []string) (style, error) {
+	var s style
+	var err error
+	if s.profile, err = render.LookupProfile(profile); err != nil {
+		return s, err
+	}
+	s.profile = s.profile.WithAnalytics(render.Analytics(a.cfg.Render.Analytics)).WithPolicy(a.policy)
+	if len(languages) == 0 {
+		languages = []string{""}
+	}
+	for _, lang := range languages {
+		cat, err := render.LookupCatalog(lang)
+		if err != nil {
+			return s, err
+		}
+		s.languages = append(s.languages, cat)
+	}
+	return s, nil
+}
+
+// contentPolicy resolves the configured preset and applies overrides.
+func contentPolicy(cfg config.ContentPolicy) (*content.Policy, error) {
+	policy, err := content.LookupPolicy(cfg.Preset)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Tags != nil {
+		policy.Tags = cfg.Tags
+	}
+	if cfg.Attributes != nil {
+		policy.Attributes = cfg.Attributes
+	}
+	if cfg.Iframes != nil {
+		policy.Iframes = *cfg.Iframes
+	}
+	if cfg.Tables != nil {
+		policy.Tables = *cfg.Tables
+	}
+	if cfg.Styles != nil {
+		policy.Styles = *cfg.Styles
+	}
+	return policy, nil
+}
+
+// style returns the render style for a page.
+func (a *App) style(file string) style {
+	if s, ok := a.styles[file]; ok {
+		return s
+	}
+	return a.defaultStyle
+}
+
+// mirrors returns the render.mirrors pages for a pipeline's output.
+func (a *App) mirrors(p pipeline, out *output) []page {
+	var pages []page
+	for _, m := range a.cfg.Render.Mirrors {
+		if m.Pipeline == p.Name() && out.restyle != nil {
+			pages = append(pages, page{file: m.File, block: out.restyle(a.style(m.File))})
+		}
+	}
+	return pages
+}
+
+// build runs every pipeline concurrently. They share one client, so
+// TeamUnify sees a single rate-limited crawler. Summaries are built after
+// the pipelines whose content they reuse.
+func (a *App) build(ctx context.Context, pipelines []pipeline, rep *report.Report) ([]*output, error) {
+	outputs := make([]*output, len(pipelines))
+	sections := make([]*report.Pipeline, len(pipelines))
+	for i, p := range pipelines {
+		sections[i] = rep.Add(p.Name(), p.File())
+	}
+
+	if err := a.buildWave(ctx, pipelines, sections, outputs, false); err != nil {
+		return outputs, err
+	}
+	return outputs, a.buildWave(ctx, pipelines, sections, outputs, true)
+}
+
+// buildWave builds either the summaries or every other pipeline.
+func (a *App) buildWave(ctx context.Context, pipelines []pipeline, sections []*report.Pipeline, outputs []*output, summaries bool) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range pipelines {
+		if _, ok := p.(summarizer); ok != summaries {
+			continue
+		}
+		g.Go(func() error {
+			out, err := p.Build(gctx, sections[i])
+			if err != nil {
+				return fmt.Errorf("%s: %w", p.Name(), err)
+			}
+			if out != nil {
+				out.extras = append(out.extras, a.mirrors(p, out)...)
+			}
+			outputs[i] = out
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// notifyEdits announces items that changed upstream after they were
+// published. Coaches tend to edit posted meet information silently.
+func (a *App) notifyEdits(pipelines []pipeline, rep *report.Report) {
+	var lines []string
+	for i, p := range pipelines {
+		changes := rep.Pipelines[i].Changes
+		if changes == nil || changes.Initial {
+			continue
+		}
+		for _, item := range changes.Updated {
+			lines = append(lines, fmt.Sprintf("%s %q", p.Noun(), item.Title))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	message := "edited on TeamUnify after publishing:\n" + strings.Join(lines, "\n")
+	if err := a.notifier.Send("info", message); err != nil {
+		a.log.Warnf("failed to send notification: %v", err)
+	}
+}
+
+// reportFrozen logs and announces what a sync would have changed during a
+// read-only freeze window.
+func (a *App) reportFrozen(pipelines []pipeline, outputs []*output, now time.Time, freeze *config.Freeze) {
+	sections, diffs, err := a.preview(pipelines, outputs, now)
+	if err != nil {
+		a.log.Fatal(err)
+	}
+
+	if len(diffs) == 0 {
+		a.log.Infof("freeze window active (%s): no changes pending", freeze.Reason)
+		return
+	}
+
+	summary := changelog.CommitBody(sections)
+	a.log.Infof("freeze window active (%s): not publishing pending changes\n%s", freeze.Reason, summary)
+	message := fmt.Sprintf("freeze window active until %s (%s); changes held back:\n%s", freeze.End.Format(time.RFC1123), freeze.Reason, summary)
+	if err := a.notifier.Send("info", message); err != nil {
+		a.log.Warnf("failed to send notification: %v", err)
+	}
+}
+
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	flags := addCommonFlags(fs)
+	fs.Parse(args)
+
+	log := setupLogger()
+	log.Infof("starting %s sync process", *flags.only)
+
+	if !*flags.offline && secrets.Get("PAT_TOKEN") == "" {
+		log.Fatal("missing PAT_TOKEN (environment, PAT_TOKEN_FILE, secrets file or keyring)")
+	}
+
+	a, pipelines := setup(log, flags)
+	a.checkStale(time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.RunDeadline.Duration)
+	defer cancel()
+
+	rep := report.New()
+	if err := hooks.Run(ctx, hooks.PreFetch, a.cfg.Hooks.PreFetch, rep, log); err != nil {
+		log.Fatal(err)
+	}
+
+	outputs, err := a.build(ctx, pipelines, rep)
+	var blocked *fetch.BlockedError
+	if errors.As(err, &blocked) {
+		if err := a.notifier.Send("error", "TeamUnify "+blocked.Error()+"; published content left untouched"); err != nil {
+			log.Warnf("failed to send notification: %v", err)
+		}
+	}
+	// A down TeamUnify is not our failure: say so once and keep the
+	// published pages until the next run
+	var unavailable *fetch.UnavailableError
+	if errors.As(err, &unavailable) {
+		if err := a.notifier.Send("degraded", "TeamUnify "+unavailable.Error()+"; skipped the rest of the run and left published content untouched"); err != nil {
+			log.Warnf("failed to send notification: %v", err)
+		}
+		log.Warnf("TeamUnify unavailable, sync skipped: %v", err)
+		return
+	}
+	if err != nil {
+		log.Fatalf("failed to fetch content: %v", err)
+	}
+	a.reportDegraded(rep)
+	succeeded := !rep.Degraded
+
+	now := time.Now()
+	freeze := a.cfg.ActiveFreeze(now)
+	if freeze != nil && freeze.Branch == "" {
+		a.reportFrozen(pipelines, outputs, now, freeze)
+		return
+	}
+
+	st := a.state
+
+	var changed []pipeline
+	var entries []changelog.Section
+	var files []string
+	for i, p := range pipelines {
+		out := outputs[i]
+		if out == nil {
+			continue
+		}
+
+		start := time.Now()
+		var modified bool
+		if !out.dataOnly {
+			log.Infof("updating %s", p.File())
+			var err error
+			if modified, err = site.Patch(p.File(), out.block); err != nil {
+				log.Fatalf("failed to update html: %v", err)
+			}
+		}
+
+		for _, extra := range out.extras {
+			extraModified, err := extra.write()
+			if err != nil {
+				log.Fatalf("failed to update html: %v", err)
+			}
+			if extraModified {
+				log.Infof("%s updated successfully", extra.file)
+				files = append(files, extra.file)
+				modified = modified || extra.primary
+			}
+			if extra.name != "" && (extraModified || modified) {
+				st.Pipelines[extra.name] = extra.items
+				modified = true
+			}
+		}
+		rep.Pipelines[i].Timings.Since("patch", start)
+
+		if !modified {
+			log.Infof("no changes detected in %s", p.File())
+			continue
+		}
+
+		if !out.dataOnly {
+			log.Infof("%s updated successfully", p.File())
+			files = append(files, p.File())
+		}
+		changes := state.Diff(st.Pipelines[p.Name()], out.items, now)
+		st.Pipelines[p.Name()] = out.items
+		rep.Pipelines[i].Modified = true
+		rep.Pipelines[i].Changes = &changes
+		rep.Modified = true
+		changed = append(changed, p)
+		entries = append(entries, changelog.Section{Name: p.Name(), Noun: p.Noun(), Changes: changes})
+	}
+
+	// The last success only advances in the committed state when there is
+	// something else to commit, so it is committed on its own once it would
+	// otherwise fall halfway to the watchdog limit
+	heartbeat := succeeded && len(changed) == 0 && a.heartbeatDue(now)
+	if succeeded {
+		st.LastSuccess = now
+	}
+	commit := len(changed) > 0 || heartbeat || a.retryChanged
+	if commit || succeeded {
+		if err := st.Save(a.cfg.StateFile); err != nil {
+			log.Fatalf("failed to save state: %v", err)
+		}
+	}
+	if commit {
+		files = append(files, a.cfg.StateFile)
+
+		if entry := changelog.Entry(now, entries); entry != "" && a.cfg.Changelog != "" {
+			if err := changelog.Append(a.cfg.Changelog, entry); err != nil {
+				log.Fatalf("failed to update changelog: %v", err)
+			}
+			files = append(files, a.cfg.Changelog)
+		}
+	}
+
+	if err := hooks.Run(ctx, hooks.PostRender, a.cfg.Hooks.PostRender, rep, log); err != nil {
+		log.Fatal(err)
+	}
+
+	if a.offline {
+		log.Infof("offline mode: wrote %s, skipping git and publishing", strings.Join(rep.OutputFiles(), ", "))
+		a.logTimings(rep)
+		return
+	}
+
+	git := newGit(a.cfg, ".", log)
+	if freeze != nil {
+		git.Branch = freeze.Branch
+	}
+	remotes, mirrors := split(a.destinations(git, freeze != nil))
+
+	var failed []string
+	if commit {
+		if err := hooks.Run(ctx, hooks.PreCommit, a.cfg.Hooks.PreCommit, rep, log); err != nil {
+			log.Fatal(err)
+		}
+
+		if freeze != nil {
+			log.Infof("freeze window active (%s): publishing to branch %s", freeze.Reason, freeze.Branch)
+		}
+		message := stateCommit
+		if len(changed) > 0 {
+			var subjects []string
+			for _, p := range changed {
+				subjects = append(subjects, p.Subject())
+			}
+			message = commitPrefix + strings.Join(subjects, " and ") + " [skip ci]"
+		}
+		if body := changelog.CommitBody(entries); body != "" {
+			message += "\n\n" + body
+		}
+		if actor, ok := triggeringActor(); ok && a.cfg.Publish.CoAuthorTrigger {
+			message += "\n\n" + actor.Trailer()
+		}
+		start := time.Now()
+		if err := git.Commit(files, message); err != nil {
+			log.Fatalf("failed to commit changes: %v", err)
+		}
+		rep.Timings.Since("commit", start)
+
+		start = time.Now()
+		failed = a.publishAll(ctx, remotes, files, rep)
+		rep.Timings.Since("push", start)
+		if len(failed) == len(remotes) && len(remotes) > 0 {
+			log.Fatalf("failed to push to %s", strings.Join(failed, ", "))
+		}
+		rep.Pushed = true
+		if a.cfg.Search.SiteURL != "" {
+			rep.URLs = search.PageURLs(a.cfg.Search.SiteURL, files)
+		}
+
+		if err := hooks.Run(ctx, hooks.PostPush, a.cfg.Hooks.PostPush, rep, log); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(mirrors) > 0 {
+		start := time.Now()
+		failed = append(failed, a.publishAll(ctx, mirrors, publishedFiles(pipelines, outputs), rep)...)
+		rep.Timings.Since("mirror", start)
+	}
+	if len(failed) > 0 {
+		a.reportFailedDestinations(failed)
+	}
+
+	if freeze != nil {
+		log.Info("freeze window active: skipping external publishing")
+	} else {
+		start := time.Now()
+		a.publishExternal(ctx, pipelines, rep)
+		rep.Timings.Since("external_publish", start)
+	}
+	a.logTimings(rep)
+
+	if len(failed) > 0 {
+		log.Fatalf("sync completed, but publishing to %s failed", strings.Join(failed, ", "))
+	}
+	log.Info("sync process completed successfully")
+}
+
+// logTimings logs how long each stage took as fields, e.g.
+// news.article_fetch=3.2, so log collectors can chart them.
+func (a *App) logTimings(rep *report.Report) {
+	fields := logrus.Fields{}
+	for _, p := range rep.Pipelines {
+		for stage, seconds := range p.Timings {
+			fields[p.Name+"."+stage] = seconds
+		}
+	}
+	for stage, seconds := range rep.Timings {
+		fields[stage] = seconds
+	}
+	a.log.WithFields(fields).Info("stage timings")
+}
+
+// checkStale alerts when no sync has succeeded for longer than
+// watchdog.max_age, so runs that keep failing or coming back degraded
+// don't go unnoticed for weeks. A state that has been synced before but
+// records no success counts as stale. Planned freezes without a branch are
+// expected to be quiet.
+func (a *App) checkStale(now time.Time) {
+	limit := a.cfg.Watchdog.MaxAge.Duration
+	last := a.state.LastSuccess
+	if limit <= 0 || (last.IsZero() && len(a.state.Pipelines) == 0) || (!last.IsZero() && now.Sub(last) <= limit) {
+		return
+	}
+	if freeze := a.cfg.ActiveFreeze(now); freeze != nil && freeze.Branch == "" {
+		return
+	}
+
+	var message string
+	if last.IsZero() {
+		a.log.Warn("no successful sync recorded")
+		message = "no successful sync has been recorded; check that scheduled syncs are running and succeeding"
+	} else {
+		age := now.Sub(last).Round(time.Hour)
+		a.log.Warnf("no successful sync since %s (%s ago)", last.Format(time.RFC3339), age)
+		message = fmt.Sprintf("no sync has succeeded since %s (%s ago); check that scheduled syncs are running and succeeding", last.Format(time.RFC3339), age)
+	}
+	if err := a.notifier.Send("stale", message); err != nil {
+		a.log.Warnf("failed to send notification: %v", err)
+	}
+}
+
+// heartbeatDue reports whether the committed last success is old enough
+// to be worth a commit of its own.
+func (a *App) heartbeatDue(now time.Time) bool {
+	limit := a.cfg.Watchdog.MaxAge.Duration
+	last := a.state.LastSuccess
+	return limit > 0 && (last.IsZero() || now.Sub(last) > limit/2)
+}
+
+// reportDegraded alerts about pipelines that kept their published content
+// because they came back empty. That is far more likely a markup or feed
+// change than the team having nothing to show, so a person should look.
+func (a *App) reportDegraded(rep *report.Report) {
+	var reasons []string
+	for _, p := range rep.Pipelines {
+		if p.Degraded != "" {
+			a.log.Warnf("%s: %s, keeping the published %s", p.Name, p.Degraded, p.OutputFile)
+			reasons = append(reasons, fmt.Sprintf("%s: %s, kept the published %s", p.Name, p.Degraded, p.OutputFile))
+		}
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	rep.Degraded = true
+	if err := a.notifier.Send("degraded", strings.Join(reasons, "\n")); err != nil {
+		a.log.Warnf("failed to send notification: %v", err)
+	}
+}
+
+// publishExternal runs each pipeline's own publishing (Ghost, newsletter
+// drafts) and the notifications that follow a live update.
+func (a *App) publishExternal(ctx context.Context, pipelines []pipeline, rep *report.Report) {
+	for i, p := range pipelines {
+		if err := p.Publish(ctx, rep.Pipelines[i]); err != nil {
+			a.log.Fatalf("failed to publish %s: %v", p.Name(), err)
+		}
+	}
+	a.notifyEdits(pipelines, rep)
+	a.announce(ctx, rep.URLs)
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/notify"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+func TestReportDegraded(t *testing.T) {
+	var sent map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	a := &App{log: log, notifier: &notify.Notifier{WebhookURL: srv.URL, Format: notify.FormatJSON, Client: srv.Client()}}
+
+	rep := report.New()
+	rep.Add("news", "news.html")
+	a.reportDegraded(rep)
+	if rep.Degraded || sent != nil {
+		t.Fatalf("healthy run reported degraded: %v %v", rep.Degraded, sent)
+	}
+
+	rep.Add("calendar", "calendar.html").Degraded = "no events parsed"
+	a.reportDegraded(rep)
+	if !rep.Degraded {
+		t.Error("run not marked degraded")
+	}
+	if sent["level"] != "degraded" || !strings.Contains(sent["message"], "calendar: no events parsed, kept the published calendar.html") {
+		t.Errorf("unexpected notification %v", sent)
+	}
+}
+
+func TestCheckStale(t *testing.T) {
+	var sent map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+	}))
+	defer srv.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := &App{
+		log:      log,
+		cfg:      config.Default(),
+		state:    &state.State{LastSuccess: now.Add(-10 * 24 * time.Hour)},
+		notifier: &notify.Notifier{WebhookURL: srv.URL, Format: notify.FormatJSON, Client: srv.Client()},
+	}
+
+	a.checkStale(now)
+	if sent != nil {
+		t.Fatalf("recent publish reported stale: %v", sent)
+	}
+
+	a.state.LastSuccess = now.Add(-20 * 24 * time.Hour)
+	a.cfg.Freezes = []config.Freeze{{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}}
+	a.checkStale(now)
+	if sent != nil {
+		t.Fatalf("freeze reported stale: %v", sent)
+	}
+
+	a.cfg.Freezes = nil
+	a.checkStale(now)
+	if sent["level"] != "stale" || !strings.Contains(sent["message"], "since 2024-05-12T12:00:00Z (480h0m0s ago)") {
+		t.Errorf("unexpected notification %v", sent)
+	}
+
+	sent = nil
+	a.state = &state.State{}
+	a.checkStale(now)
+	if sent != nil {
+		t.Fatalf("first run reported stale: %v", sent)
+	}
+
+	a.state.Pipelines = map[string][]state.Item{"news": nil}
+	a.checkStale(now)
+	if sent["level"] != "stale" || !strings.Contains(sent["message"], "no successful sync") {
+		t.Errorf("unexpected notification %v", sent)
+	}
+}
+
+func TestHeartbeatDue(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := &App{cfg: config.Default(), state: &state.State{}}
+	if !a.heartbeatDue(now) {
+		t.Error("missing last success is not due")
+	}
+	a.state.LastSuccess = now.Add(-6 * 24 * time.Hour)
+	if a.heartbeatDue(now) {
+		t.Error("recent success is due")
+	}
+	a.state.LastSuccess = now.Add(-8 * 24 * time.Hour)
+	if !a.heartbeatDue(now) {
+		t.Error("success past half the watchdog limit is not due")
+	}
+}
+
+func TestTriggeringActor(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "schedule")
+	t.Setenv("GITHUB_ACTOR", "coach")
+	t.Setenv("GITHUB_ACTOR_ID", "4242")
+	t.Setenv("GITHUB_TRIGGERING_ACTOR", "coach")
+	if _, ok := triggeringActor(); ok {
+		t.Error("scheduled run credited a person")
+	}
+
+	t.Setenv("GITHUB_EVENT_NAME", "workflow_dispatch")
+	actor, ok := triggeringActor()
+	if !ok || actor.Trailer() != "Co-authored-by: coach <4242+coach@users.noreply.github.com>" {
+		t.Errorf("unexpected co-author %q", actor.Trailer())
+	}
+
+	// A re-run by someone else keeps the original actor's id
+	t.Setenv("GITHUB_TRIGGERING_ACTOR", "volunteer")
+	if actor, _ := triggeringActor(); actor.Email != "volunteer@users.noreply.github.com" {
+		t.Errorf("unexpected co-author %q", actor.Trailer())
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const (
+	icsURL     = "https://www.gomotionapp.com/rest/ics/system/5/Events.ics?key=l4eIgFXwqEbxbQz42YjRgg%3D%3D&enabled=false&tz=America%2FLos_Angeles"
+	timezone   = "America/Los_Angeles"
+	eventsHTML = "calendar.html"
+)
+
+type calendarPipeline struct {
+	app     *App
+	fetcher *calendar.Fetcher
+	events  []gocal.Event
+}
+
+func (a *App) newCalendarPipeline() (*calendarPipeline, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone load failed: %w", err)
+	}
+
+	strip, err := calendar.NewPolicy(a.cfg.Calendar.Strip)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := calendar.NewFilter(calendar.Rule(a.cfg.Calendar.Include), calendar.Rule(a.cfg.Calendar.Exclude))
+	if err != nil {
+		return nil, err
+	}
+
+	rsvp, err := calendar.NewRSVPParser(calendar.RSVPRules(a.cfg.Calendar.RSVP))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &calendarPipeline{
+		app: a,
+		fetcher: &calendar.Fetcher{
+			URL:            icsURL,
+			Location:       loc,
+			Start:          now,
+			End:            now.AddDate(0, 0, a.cfg.Calendar.DaysAhead),
+			Strip:          strip,
+			Filter:         filter,
+			RSVP:           rsvp,
+			Client:         a.client,
+			RequestTimeout: a.cfg.Fetch.RequestTimeout.Duration,
+			Log:            a.log,
+		},
+	}, nil
+}
+
+func (p *calendarPipeline) Name() string    { return "calendar" }
+func (p *calendarPipeline) File() string    { return eventsHTML }
+func (p *calendarPipeline) Subject() string { return "calendar" }
+func (p *calendarPipeline) Noun() string    { return "event" }
+
+func (p *calendarPipeline) Build(ctx context.Context, rep *report.Pipeline) (*output, error) {
+	start := time.Now()
+	events, rsvps, err := p.fetcher.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep.Timings.Since("fetch", start)
+	start = time.Now()
+	if len(events) == 0 {
+		rep.Degraded = "no events parsed"
+		return nil, nil
+	}
+	rep.Items = len(events)
+	p.events = events
+
+	// Classify against the team's clock rather than the runner's
+	now := time.Now().In(p.fetcher.Location)
+	p.adoptLegacyKeys(events)
+	items := make([]state.Item, 0, len(events))
+	var records []record
+	for _, event := range events {
+		if calendar.Past(event, now) {
+			continue
+		}
+		key := calendar.ID(event)
+		records = append(records, record{
+			Pipeline: p.Name(),
+			Key:      key,
+			Title:    event.Summary,
+			Date:     event.Start.Format("January 02, 2006"),
+			URL:      event.URL,
+			Start:    event.Start.Format(time.RFC3339),
+			End:      event.End.Format(time.RFC3339),
+			Location: event.Location,
+			Content:  event.Description,
+		})
+		items = append(items, state.Item{
+			Key:     key,
+			Title:   event.Summary,
+			Date:    event.Start.Format("January 02, 2006"),
+			Hash:    state.Hash(event.Summary, event.Start.String(), event.End.String()),
+			Expires: *event.End,
+		})
+	}
+
+	clock := p.app.clock()
+	p.app.log.Info("generating html content")
+	restyle := func(s style) string {
+		return "\n" + render.Localized(s.languages, func(cat *render.Catalog) string {
+			return s.profile.Calendar(events, rsvps, now, clock, cat)
+		}) + "\n"
+	}
+	out := &output{block: restyle(p.app.style(p.File())), items: items, restyle: restyle, records: records}
+	if file := p.app.cfg.Print.CalendarFile; file != "" {
+		out.extras = append(out.extras, page{
+			file:       file,
+			block:      render.PrintPage("Calendar", render.CalendarPrint(events, now, clock)),
+			standalone: true,
+		})
+	}
+	data, ok, err := p.app.dataPage("events", records)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		out.extras = append(out.extras, data)
+		out.dataOnly = data.primary
+	}
+	rep.Timings.Since("render", start)
+	return out, nil
+}
+
+// adoptLegacyKeys renames state items keyed by UID and start, as before
+// events had stable ids, so the switch isn't reported as every event being
+// replaced.
+func (p *calendarPipeline) adoptLegacyKeys(events []gocal.Event) {
+	renamed := make(map[string]string, len(events))
+	for _, event := range events {
+		renamed[event.Uid+"@"+event.Start.Format(time.RFC3339)] = calendar.ID(event)
+	}
+
+	items := p.app.state.Pipelines[p.Name()]
+	for i := range items {
+		if key, ok := renamed[items[i].Key]; ok {
+			items[i].Key = key
+		}
+	}
+}
+
+func (p *calendarPipeline) Publish(ctx context.Context, rep *report.Pipeline) error {
+	return nil
+}
+
+// clock is the calendar.clock time layout.
+func (a *App) clock() string {
+	if a.cfg.Calendar.Clock == "24h" {
+		return render.Clock24h
+	}
+	return render.Clock12h
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// dataEntry is an article or event as a site generator's templates see
+// it. It leaves out the pipeline name and first seen date so the file only
+// changes when the content does.
+type dataEntry struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Date     string `json:"date,omitempty"`
+	Author   string `json:"author,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Location string `json:"location,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// dataPage returns the data file named name for records, or false when
+// data.format is unset.
+func (a *App) dataPage(name string, records []record) (page, bool, error) {
+	cfg := a.cfg.Data
+	if cfg.Format == "" {
+		return page{}, false, nil
+	}
+
+	entries := make([]dataEntry, 0, len(records))
+	for _, r := range records {
+		entries = append(entries, dataEntry{
+			ID:       r.Key,
+			Title:    r.Title,
+			Date:     r.Date,
+			Author:   r.Author,
+			URL:      r.URL,
+			Start:    r.Start,
+			End:      r.End,
+			Location: r.Location,
+			Content:  r.Content,
+			Markdown: r.Markdown,
+		})
+	}
+
+	var buf bytes.Buffer
+	ext := ".json"
+	if cfg.Format == "jekyll" {
+		ext = ".yml"
+		if err := writeYAML(&buf, entries); err != nil {
+			return page{}, false, err
+		}
+	} else {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(entries); err != nil {
+			return page{}, false, fmt.Errorf("json encode failed: %w", err)
+		}
+	}
+
+	return page{
+		file:       filepath.Join(cfg.Dir, name+ext),
+		block:      buf.String(),
+		standalone: true,
+		primary:    cfg.Only,
+	}, true, nil
+}
+
+// writeYAML writes entries as a YAML list. Strings are written as JSON
+// strings, which YAML reads as double-quoted scalars, so content needs no
+// further escaping.
+func writeYAML(w io.Writer, entries []dataEntry) error {
+	if len(entries) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	var b strings.Builder
+	t := reflect.TypeOf(dataEntry{})
+	for _, entry := range entries {
+		v := reflect.ValueOf(entry)
+		prefix := "- "
+		for i := 0; i < t.NumField(); i++ {
+			value := v.Field(i).String()
+			name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if value == "" && opts == "omitempty" {
+				continue
+			}
+
+			quoted, err := yamlString(value)
+			if err != nil {
+				return err
+			}
+			b.WriteString(prefix + name + ": " + quoted + "\n")
+			prefix = "  "
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func yamlString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", fmt.Errorf("yaml encode failed: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
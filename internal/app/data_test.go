@@ -0,0 +1,50 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+)
+
+func TestDataPage(t *testing.T) {
+	records := []record{{
+		Pipeline:  "news",
+		Key:       "https://example.com/a",
+		Title:     `Meet "Results"`,
+		Date:      "May 01, 2024",
+		URL:       "https://example.com/a",
+		FirstSeen: "2024-05-01T00:00:00Z",
+		Content:   "<p>Line one\nline two</p>",
+	}}
+
+	a := &App{cfg: &config.Config{Data: config.Data{Format: "jekyll", Dir: "_data", Only: true}}}
+	pg, ok, err := a.dataPage("news", records)
+	if err != nil || !ok {
+		t.Fatalf("dataPage: %v %v", ok, err)
+	}
+	want := `- id: "https://example.com/a"
+  title: "Meet \"Results\""
+  date: "May 01, 2024"
+  url: "https://example.com/a"
+  content: "<p>Line one\nline two</p>"
+`
+	if pg.file != "_data/news.yml" || pg.block != want || !pg.standalone || !pg.primary {
+		t.Errorf("unexpected page %s:\n%s", pg.file, pg.block)
+	}
+
+	a.cfg.Data.Format = "eleventy"
+	pg, _, err = a.dataPage("events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []dataEntry
+	if pg.file != "_data/events.json" || json.Unmarshal([]byte(pg.block), &entries) != nil || entries == nil {
+		t.Errorf("unexpected page %s:\n%s", pg.file, pg.block)
+	}
+
+	a.cfg.Data.Format = ""
+	if _, ok, _ := a.dataPage("news", records); ok {
+		t.Error("data file written without a format")
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/changelog"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/state"
+	"github.com/dareaquatics/dare-website/internal/textdiff"
+)
+
+// runDiff prints what the next sync would change without writing files,
+// running hooks or touching git.
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags := addCommonFlags(fs)
+	fs.Parse(args)
+
+	log := setupLogger()
+	a, pipelines := setup(log, flags)
+	// A preview announces nothing; the retry queue in particular would
+	// otherwise report an article the real sync reports again
+	a.notifier = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.RunDeadline.Duration)
+	defer cancel()
+
+	outputs, err := a.build(ctx, pipelines, report.New())
+	if err != nil {
+		log.Fatalf("failed to fetch content: %v", err)
+	}
+
+	sections, diffs, err := a.preview(pipelines, outputs, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(diffs) == 0 {
+		fmt.Println("no changes")
+		return
+	}
+
+	if body := changelog.CommitBody(sections); body != "" {
+		fmt.Println(body)
+	}
+	for _, diff := range diffs {
+		fmt.Print(diff)
+	}
+}
+
+// preview compares each pipeline's output with the page on disk and returns
+// the item changes and block diffs for the pages that would change.
+func (a *App) preview(pipelines []pipeline, outputs []*output, now time.Time) ([]changelog.Section, []string, error) {
+	var sections []changelog.Section
+	var diffs []string
+	for i, p := range pipelines {
+		out := outputs[i]
+		if out == nil {
+			continue
+		}
+
+		pages := out.extras
+		if !out.dataOnly {
+			pages = append([]page{{file: p.File(), block: out.block}}, pages...)
+		}
+		pageDiffs, err := blockDiffs(pages)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(pageDiffs) == 0 {
+			continue
+		}
+
+		diffs = append(diffs, pageDiffs...)
+		sections = append(sections, changelog.Section{
+			Name:    p.Name(),
+			Noun:    p.Noun(),
+			Changes: state.Diff(a.state.Pipelines[p.Name()], out.items, now),
+		})
+	}
+	return sections, diffs, nil
+}
+
+// blockDiffs diffs each page's marker block on disk against its new block.
+func blockDiffs(pages []page) ([]string, error) {
+	var diffs []string
+	for _, pg := range pages {
+		current, err := pg.current()
+		if err != nil {
+			return nil, err
+		}
+
+		if diff := textdiff.Unified(pg.file, pg.file+" (next sync)", current, pg.block, 3); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/report"
+)
+
+// record is one article or event in an export. Fields a source doesn't
+// have are left empty; the state store only knows key, title, date and
+// first seen.
+type record struct {
+	Pipeline  string `json:"pipeline"`
+	Key       string `json:"key"`
+	Title     string `json:"title"`
+	Date      string `json:"date,omitempty"`
+	Author    string `json:"author,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Start     string `json:"start,omitempty"`
+	End       string `json:"end,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Status    string `json:"status,omitempty"`
+	FirstSeen string `json:"first_seen,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Markdown  string `json:"markdown,omitempty"`
+}
+
+var csvHeader = []string{"pipeline", "key", "title", "date", "author", "url", "start", "end", "location", "status", "first_seen", "content", "markdown"}
+
+func (r record) csv() []string {
+	return []string{r.Pipeline, r.Key, r.Title, r.Date, r.Author, r.URL, r.Start, r.End, r.Location, r.Status, r.FirstSeen, r.Content, r.Markdown}
+}
+
+// runExport writes every known article and event as JSON or CSV, from the
+// state file by default or from a fresh fetch with --fresh.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	flags := addCommonFlags(fs)
+	format := fs.String("format", "json", "output format, json or csv")
+	fresh := fs.Bool("fresh", false, "fetch from TeamUnify instead of reading the state file")
+	out := fs.String("out", "", "file to write instead of stdout")
+	fs.Parse(args)
+
+	log := setupLogger()
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("unknown export format %q", *format)
+	}
+
+	// Resolve before setup changes into the website repository
+	path := *out
+	if path != "" {
+		var err error
+		if path, err = filepath.Abs(path); err != nil {
+			log.Fatalf("failed to resolve %s: %v", *out, err)
+		}
+	}
+
+	a, pipelines := setup(log, flags)
+
+	var records []record
+	if *fresh {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.RunDeadline.Duration)
+		defer cancel()
+
+		outputs, err := a.build(ctx, pipelines, report.New())
+		if err != nil {
+			log.Fatalf("failed to fetch content: %v", err)
+		}
+		for _, o := range outputs {
+			if o != nil {
+				records = append(records, o.records...)
+			}
+		}
+	} else {
+		records = a.stateRecords(pipelines)
+	}
+
+	w := io.Writer(os.Stdout)
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeRecords(w, *format, records); err != nil {
+		log.Fatalf("failed to write export: %v", err)
+	}
+	if path != "" {
+		log.Infof("exported %d records to %s", len(records), path)
+	}
+}
+
+// stateRecords lists what the last sync published for the selected
+// pipelines, including pages they own such as the news archive.
+func (a *App) stateRecords(pipelines []pipeline) []record {
+	names := make([]string, 0, len(a.state.Pipelines))
+	for name := range a.state.Pipelines {
+		for _, p := range pipelines {
+			if strings.HasPrefix(name, p.Name()) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var records []record
+	for _, name := range names {
+		for _, item := range a.state.Pipelines[name] {
+			r := record{Pipeline: name, Key: item.Key, Title: item.Title, Date: item.Date}
+			if !item.FirstSeen.IsZero() {
+				r.FirstSeen = item.FirstSeen.UTC().Format(time.RFC3339)
+			}
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func writeRecords(w io.Writer, format string, records []record) error {
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return fmt.Errorf("csv write failed: %w", err)
+		}
+		for _, r := range records {
+			if err := cw.Write(r.csv()); err != nil {
+				return fmt.Errorf("csv write failed: %w", err)
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	if records == nil {
+		records = []record{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(records)
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/site"
+)
+
+// runImport seeds the state file from the articles already on news.html
+// (and the archive page), so the first incremental sync on an existing
+// deployment starts from what is published instead of from nothing. Only
+// the listing is fetched, to recover each article's URL.
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	flags := addCommonFlags(fs)
+	force := fs.Bool("force", false, "replace news entries already in the state file")
+	fs.Parse(args)
+	*flags.only = "news"
+
+	log := setupLogger()
+	a, pipelines := setup(log, flags)
+	p := pipelines[0].(*newsPipeline)
+
+	if len(a.state.Pipelines[p.Name()]) > 0 && !*force {
+		log.Fatalf("%s already tracks %d articles; rerun with --force to replace them", a.cfg.StateFile, len(a.state.Pipelines[p.Name()]))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.RunDeadline.Duration)
+	defer cancel()
+
+	listing, err := p.scraper.Listing(ctx)
+	if err != nil {
+		log.Fatalf("failed to fetch news listing: %v", err)
+	}
+	urls := make(map[string]string, len(listing))
+	for _, entry := range listing {
+		urls[strings.ToLower(entry.Title)] = entry.URL
+	}
+
+	pages := map[string]string{p.Name(): p.File()}
+	if a.cfg.News.ArchiveFile != "" {
+		pages[archiveName] = a.cfg.News.ArchiveFile
+	}
+	for name, file := range pages {
+		articles, err := readNewsPage(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var matched []news.Article
+		for _, article := range articles {
+			url, ok := urls[strings.ToLower(article.Title)]
+			if !ok {
+				log.Warnf("skipping %q from %s: not on the news listing", article.Title, file)
+				continue
+			}
+			article.URL = url
+			matched = append(matched, article)
+		}
+
+		a.state.Pipelines[name] = p.items(matched)
+		log.Infof("imported %d of %d articles from %s", len(matched), len(articles), file)
+	}
+
+	if err := a.state.Save(a.cfg.StateFile); err != nil {
+		log.Fatalf("failed to save state: %v", err)
+	}
+	log.Infof("wrote %s; commit it with the site so the next sync picks it up", a.cfg.StateFile)
+}
+
+func readNewsPage(file string) ([]news.Article, error) {
+	html, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	block, err := site.Block(string(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return parseNewsBlock(block)
+}
+
+// parseNewsBlock reads articles back out of a rendered news block. It
+// works for every render profile, and for blocks written before articles
+// had ids: each article is the element around an h2 title, with author and
+// published lines and a content div. Bilingual blocks are read in English.
+func parseNewsBlock(block string) ([]news.Article, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(block))
+	if err != nil {
+		return nil, fmt.Errorf("html parsing failed: %w", err)
+	}
+
+	root := doc.Selection
+	if en := doc.Find(`[lang="en"]`); en.Length() > 0 {
+		root = en.First()
+	}
+
+	var articles []news.Article
+	root.Find("h2").Each(func(i int, h2 *goquery.Selection) {
+		item := h2.Parent()
+		article := news.Article{Title: content.Clean(h2.Text()), Date: news.UnknownDate}
+
+		item.ChildrenFiltered("p").Each(func(i int, p *goquery.Selection) {
+			text := strings.TrimSpace(p.Text())
+			if rest, ok := strings.CutPrefix(text, render.English.Author); ok {
+				article.Author = content.Clean(rest)
+			} else if rest, ok := strings.CutPrefix(text, render.English.Published); ok {
+				article.Date = strings.TrimSpace(rest)
+			}
+		})
+		if published, err := time.Parse(news.TimeFormat, article.Date); err == nil {
+			article.Published = published
+		}
+		article.Content, _ = item.ChildrenFiltered("div").First().Html()
+
+		articles = append(articles, article)
+	})
+	return articles, nil
+}
@@ -0,0 +1,497 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/ghost"
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/newsletter"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const (
+	newsURL      = "https://www.gomotionapp.com/team/cadas/page/news"
+	baseURL      = "https://www.gomotionapp.com"
+	newsHTMLFile = "news.html"
+	concurrency  = 5
+	archiveName  = "news-archive"
+)
+
+type newsPipeline struct {
+	app      *App
+	scraper  *news.Scraper
+	articles []news.Article
+	// live and archived are the articles on the news page and those moved
+	// to the archive by the last Build.
+	live, archived []news.Article
+	// loc is the team's timezone, for the dates edits are shown with.
+	loc *time.Location
+}
+
+func (a *App) newNewsPipeline() (*newsPipeline, error) {
+	pipeline, err := content.NewPipeline(a.cfg.Content.Transformers, content.Options{BaseURL: baseURL, Policy: a.policy})
+	if err != nil {
+		return nil, fmt.Errorf("content pipeline: %w", err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone load failed: %w", err)
+	}
+
+	p := &newsPipeline{
+		app: a,
+		loc: loc,
+		scraper: &news.Scraper{
+			ListingURL:     newsURL,
+			BaseURL:        baseURL,
+			Concurrency:    concurrency,
+			MaxPages:       a.cfg.News.MaxPages,
+			RequestTimeout: a.cfg.Fetch.RequestTimeout.Duration,
+			Client:         a.client,
+			Pipeline:       pipeline,
+			Log:            a.log,
+		},
+	}
+	if a.cacheDir != "" && a.cfg.News.RefetchAfter.Duration > 0 {
+		p.scraper.Cache = &news.ArticleCache{
+			Path:   filepath.Join(a.cacheDir, "articles.json"),
+			MaxAge: a.cfg.News.RefetchAfter.Duration,
+		}
+	}
+	return p, nil
+}
+
+func (p *newsPipeline) Name() string    { return "news" }
+func (p *newsPipeline) File() string    { return newsHTMLFile }
+func (p *newsPipeline) Subject() string { return "news articles" }
+func (p *newsPipeline) Noun() string    { return "article" }
+
+func (p *newsPipeline) Build(ctx context.Context, rep *report.Pipeline) (*output, error) {
+	start := time.Now()
+	entries, err := p.scraper.Listing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep.Timings.Since("listing_fetch", start)
+
+	start = time.Now()
+	p.queueRetries()
+	p.scraper.Failures = &news.Failures{}
+	articles, err := p.scraper.Fetch(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	p.updateRetries(p.scraper.Failures.Errors(), time.Now())
+	rep.Timings.Since("article_fetch", start)
+	start = time.Now()
+
+	if len(articles) == 0 {
+		rep.Degraded = "no articles found"
+		return nil, nil
+	}
+	p.markEdits(articles, time.Now().In(p.loc))
+	articles = p.purge(articles, time.Now())
+	p.articles = articles
+
+	rep.Items = len(articles)
+	for _, article := range articles {
+		if article.Published.IsZero() {
+			rep.UnknownDates = append(rep.UnknownDates, article.URL)
+		}
+	}
+
+	cfg := p.app.cfg.News
+	live, archived := p.splitByAge(articles, time.Now())
+	if cfg.MaxArticles > 0 && len(live) > cfg.MaxArticles {
+		archived = append(live[cfg.MaxArticles:len(live):len(live)], archived...)
+		live = live[:cfg.MaxArticles]
+	}
+	p.live, p.archived = live, archived
+
+	href := cfg.ArchiveURL
+	if href == "" {
+		href = cfg.ArchiveFile
+	}
+	restyle := func(s style) string {
+		return render.Localized(s.languages, func(cat *render.Catalog) string {
+			block := s.profile.News(live, cat)
+			if href != "" && len(archived) > 0 {
+				block += s.profile.OlderNews(href, len(archived), cat)
+			}
+			return block
+		})
+	}
+
+	out := &output{
+		block:   restyle(p.app.style(p.File())),
+		items:   p.items(live),
+		restyle: restyle,
+		records: p.records(articles),
+	}
+	if archive := cfg.ArchiveFile; archive != "" {
+		out.extras = append(out.extras, page{
+			name:  archiveName,
+			file:  archive,
+			block: p.app.archiveBlock(archived),
+			items: p.items(archived),
+		})
+	} else if len(archived) > 0 {
+		p.app.log.Infof("leaving %d older articles off the news page", len(archived))
+	}
+
+	if file := p.app.cfg.Print.NewsFile; file != "" {
+		out.extras = append(out.extras, page{
+			file:       file,
+			block:      render.PrintPage("News", render.NewsPrint(live)),
+			standalone: true,
+		})
+	}
+	data, ok, err := p.app.dataPage("news", out.records)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		out.extras = append(out.extras, data)
+		out.dataOnly = data.primary
+	}
+	rep.Timings.Since("render", start)
+	return out, nil
+}
+
+// queueRetries puts the articles that failed last run first in line,
+// unless they have run out of attempts; those are still fetched, in their
+// listing order.
+func (p *newsPipeline) queueRetries() {
+	limit := p.app.cfg.News.RetryAttempts
+	p.scraper.Priority = make(map[string]bool, len(p.app.state.Retry))
+	for url, retry := range p.app.state.Retry {
+		if retry.Attempts < limit {
+			p.scraper.Priority[url] = true
+		}
+	}
+	if len(p.scraper.Priority) > 0 {
+		p.app.log.Infof("retrying %d articles that failed to fetch before", len(p.scraper.Priority))
+	}
+}
+
+// updateRetries records this run's failures in the retry queue; articles
+// that came through or weren't fetched are dropped. An article that has
+// run out of attempts keeps its entry unchanged, so it is neither bumped
+// nor reported again while it keeps failing.
+func (p *newsPipeline) updateRetries(failed map[string]string, now time.Time) {
+	limit := p.app.cfg.News.RetryAttempts
+	old := p.app.state.Retry
+
+	next := map[string]state.Retry{}
+	for url, msg := range failed {
+		retry := old[url]
+		if retry.Attempts >= limit {
+			next[url] = retry
+			continue
+		}
+		if retry.Attempts == 0 {
+			retry.Since = now
+		}
+		retry.Attempts++
+		retry.LastError = msg
+		next[url] = retry
+		if retry.Attempts == limit {
+			p.app.log.Warnf("no longer prioritizing %s after %d failed attempts: %s", url, retry.Attempts, msg)
+			message := fmt.Sprintf("%s has failed to fetch %d times since %s: %s", url, retry.Attempts, retry.Since.Format(time.RFC3339), msg)
+			if err := p.app.notifier.Send("degraded", message); err != nil {
+				p.app.log.Warnf("failed to send notification: %v", err)
+			}
+		}
+	}
+	if len(next) == 0 {
+		next = nil
+	}
+
+	if !reflect.DeepEqual(old, next) {
+		p.app.state.Retry = next
+		p.app.retryChanged = true
+	}
+}
+
+// archiveBlock renders the news archive page in its configured style.
+func (a *App) archiveBlock(archived []news.Article) string {
+	s := a.style(a.cfg.News.ArchiveFile)
+	return render.Localized(s.languages, func(cat *render.Catalog) string {
+		return s.profile.News(archived, cat)
+	})
+}
+
+// splitByAge separates articles older than news.max_age_days. An article's
+// age runs from the earlier of its published date and when a sync first
+// saw it, so TeamUnify re-dating an old post does not make it new again.
+func (p *newsPipeline) splitByAge(articles []news.Article, now time.Time) (live, archived []news.Article) {
+	if p.app.cfg.News.MaxAgeDays <= 0 {
+		return articles, nil
+	}
+
+	cutoff := now.AddDate(0, 0, -p.app.cfg.News.MaxAgeDays)
+	for _, article := range articles {
+		if born := p.born(article); !born.IsZero() && born.Before(cutoff) {
+			archived = append(archived, article)
+		} else {
+			live = append(live, article)
+		}
+	}
+	return live, archived
+}
+
+// purge drops articles older than retention.years from the news page,
+// the archive and everything else built from them. They leave the state
+// file on the next save.
+func (p *newsPipeline) purge(articles []news.Article, now time.Time) []news.Article {
+	years := p.app.cfg.Retention.Years
+	if years <= 0 {
+		return articles
+	}
+
+	cutoff := now.AddDate(-years, 0, 0)
+	kept := articles[:0]
+	for _, article := range articles {
+		if born := p.born(article); !born.IsZero() && born.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, article)
+	}
+	if purged := len(articles) - len(kept); purged > 0 {
+		p.app.log.Infof("purged %d articles older than %d years", purged, years)
+	}
+	return kept
+}
+
+// born is the earlier of an article's published date and when a sync
+// first saw it, or zero when neither is known.
+func (p *newsPipeline) born(article news.Article) time.Time {
+	born := p.firstSeen(article.URL)
+	if !article.Published.IsZero() && (born.IsZero() || article.Published.Before(born)) {
+		born = article.Published
+	}
+	return born
+}
+
+// previous returns what the last sync published for key, from the news
+// page or the archive.
+func (p *newsPipeline) previous(key string) (state.Item, bool) {
+	for _, name := range []string{p.Name(), archiveName} {
+		for _, item := range p.app.state.Pipelines[name] {
+			if item.Key == key {
+				return item, true
+			}
+		}
+	}
+	return state.Item{}, false
+}
+
+func (p *newsPipeline) firstSeen(key string) time.Time {
+	item, _ := p.previous(key)
+	return item.FirstSeen
+}
+
+// markEdits sets Updated on articles whose content changed since they were
+// published, keeping the date of earlier edits.
+func (p *newsPipeline) markEdits(articles []news.Article, now time.Time) {
+	for i := range articles {
+		before, ok := p.previous(articles[i].URL)
+		switch {
+		case !ok:
+		case before.Hash != hashArticle(articles[i]):
+			articles[i].Updated = now
+		default:
+			articles[i].Updated = before.UpdatedAt
+		}
+	}
+}
+
+func hashArticle(article news.Article) string {
+	return state.Hash(article.Title, article.Author, article.Date, article.Content)
+}
+
+func (p *newsPipeline) records(articles []news.Article) []record {
+	records := make([]record, 0, len(articles))
+	for _, article := range articles {
+		r := record{
+			Pipeline: p.Name(),
+			Key:      article.URL,
+			Title:    article.Title,
+			Date:     article.Date,
+			Author:   article.Author,
+			URL:      article.URL,
+			Content:  article.Content,
+			Markdown: article.Markdown,
+		}
+		if seen := p.firstSeen(article.URL); !seen.IsZero() {
+			r.FirstSeen = seen.UTC().Format(time.RFC3339)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func (p *newsPipeline) items(articles []news.Article) []state.Item {
+	now := time.Now()
+	items := make([]state.Item, 0, len(articles))
+	for _, article := range articles {
+		seen := p.firstSeen(article.URL)
+		if seen.IsZero() {
+			seen = now
+		}
+		item := state.Item{
+			Key:       article.URL,
+			Title:     article.Title,
+			Date:      article.Date,
+			Hash:      hashArticle(article),
+			FirstSeen: seen,
+			UpdatedAt: article.Updated,
+		}
+		// Purged articles expire rather than show up as removed
+		if years := p.app.cfg.Retention.Years; years > 0 {
+			born := p.born(article)
+			if born.IsZero() || seen.Before(born) {
+				born = seen
+			}
+			item.Expires = born.AddDate(years, 0, 0)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func (p *newsPipeline) Publish(ctx context.Context, rep *report.Pipeline) error {
+	if len(p.articles) == 0 {
+		return nil
+	}
+
+	if os.Getenv("GHOST_ADMIN_URL") != "" {
+		if err := p.publishToGhost(); err != nil {
+			return fmt.Errorf("ghost: %w", err)
+		}
+	}
+
+	if p.app.cfg.Newsletter.Provider != "" && rep.Changes != nil && !rep.Changes.Initial {
+		if err := p.draftNewsletter(ctx, rep.Changes.Added); err != nil {
+			return fmt.Errorf("newsletter: %w", err)
+		}
+	}
+	return nil
+}
+
+// draftNewsletter creates an unsent campaign with the articles this run
+// added, for a volunteer to review and send.
+func (p *newsPipeline) draftNewsletter(ctx context.Context, added []state.Item) error {
+	keys := map[string]bool{}
+	for _, item := range added {
+		keys[item.Key] = true
+	}
+
+	var articles []news.Article
+	for _, article := range p.articles {
+		if keys[article.URL] {
+			articles = append(articles, article)
+		}
+	}
+	if len(articles) == 0 {
+		return nil
+	}
+
+	cfg := p.app.cfg.Newsletter
+	client, err := newsletter.New(cfg.Provider, newsletter.Options{
+		APIKey:   secrets.Get("NEWSLETTER_API_KEY"),
+		ListID:   cfg.ListID,
+		FromName: cfg.FromName,
+		ReplyTo:  cfg.ReplyTo,
+	}, p.app.api)
+	if err != nil {
+		return err
+	}
+
+	id, err := client.CreateDraft(ctx, newsletter.Draft{
+		Subject:  render.DigestSubject(articles),
+		HTML:     render.Digest(articles),
+		Markdown: render.DigestMarkdown(articles),
+	})
+	if err != nil {
+		return err
+	}
+
+	p.app.log.Infof("created %s draft %s with %d articles", cfg.Provider, id, len(articles))
+	return nil
+}
+
+func (p *newsPipeline) publishToGhost() error {
+	log := p.app.log
+	log.Info("publishing articles to ghost")
+	cms, err := ghost.New(os.Getenv("GHOST_ADMIN_URL"), secrets.Get("GHOST_ADMIN_API_KEY"), p.app.api)
+	if err != nil {
+		return fmt.Errorf("ghost client setup failed: %w", err)
+	}
+
+	tags := splitList(os.Getenv("GHOST_TAGS"))
+	if len(tags) == 0 {
+		tags = []string{"News"}
+	}
+
+	// GHOST_AUTHORS maps TeamUnify author names to Ghost staff emails,
+	// e.g. "Jane Doe=jane@example.com,John Roe=john@example.com"
+	authors := map[string]string{}
+	for _, pair := range splitList(os.Getenv("GHOST_AUTHORS")) {
+		if name, email, ok := strings.Cut(pair, "="); ok {
+			authors[strings.TrimSpace(name)] = strings.TrimSpace(email)
+		}
+	}
+
+	created := 0
+	for _, article := range p.articles {
+		post := ghost.Post{
+			Title:        article.Title,
+			HTML:         article.Content,
+			Status:       "published",
+			Tags:         tags,
+			CanonicalURL: article.URL,
+		}
+
+		if email, ok := authors[article.Author]; ok {
+			post.Authors = []string{email}
+		} else if email := os.Getenv("GHOST_DEFAULT_AUTHOR"); email != "" {
+			post.Authors = []string{email}
+		}
+
+		if t, err := time.Parse(news.TimeFormat, article.Date); err == nil {
+			post.PublishedAt = t.UTC().Format(time.RFC3339)
+		}
+
+		isNew, err := cms.UpsertPost(post)
+		if err != nil {
+			return fmt.Errorf("%s: %w", article.URL, err)
+		}
+		if isNew {
+			created++
+		}
+	}
+
+	log.Infof("ghost sync complete: %d new posts", created)
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMarkEdits(t *testing.T) {
+	earlier := time.Date(2024, 4, 2, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+
+	unchanged := news.Article{URL: "a", Title: "Meet Info", Content: "<p>Warmups at 7</p>"}
+	edited := news.Article{URL: "b", Title: "Meet Results", Content: "<p>Results v2</p>"}
+	editedBefore := news.Article{URL: "c", Title: "Carpool", Content: "<p>Same</p>"}
+	added := news.Article{URL: "d", Title: "New Post"}
+
+	p := &newsPipeline{app: &App{cfg: config.Default(), state: &state.State{Pipelines: map[string][]state.Item{
+		"news": {
+			{Key: "a", Hash: hashArticle(unchanged)},
+			{Key: "b", Hash: hashArticle(news.Article{URL: "b", Title: "Meet Results", Content: "<p>Results</p>"})},
+		},
+		archiveName: {
+			{Key: "c", Hash: hashArticle(editedBefore), UpdatedAt: earlier},
+		},
+	}}}}
+
+	articles := []news.Article{unchanged, edited, editedBefore, added}
+	p.markEdits(articles, now)
+
+	want := []time.Time{{}, now, earlier, {}}
+	for i, article := range articles {
+		if !article.Updated.Equal(want[i]) {
+			t.Errorf("%s: Updated = %v, want %v", article.Title, article.Updated, want[i])
+		}
+	}
+
+	if items := p.items(articles); !items[1].UpdatedAt.Equal(now) {
+		t.Errorf("state item UpdatedAt = %v, want %v", items[1].UpdatedAt, now)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cfg := config.Default()
+	cfg.Retention.Years = 2
+	p := &newsPipeline{app: &App{cfg: cfg, log: log, state: &state.State{Pipelines: map[string][]state.Item{
+		archiveName: {{Key: "redated", FirstSeen: time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)}},
+	}}}}
+
+	articles := p.purge([]news.Article{
+		{URL: "recent", Published: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{URL: "old", Published: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{URL: "redated", Published: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{URL: "undated"},
+	}, now)
+
+	var kept []string
+	for _, article := range articles {
+		kept = append(kept, article.URL)
+	}
+	if len(kept) != 2 || kept[0] != "recent" || kept[1] != "undated" {
+		t.Errorf("kept %v, want [recent undated]", kept)
+	}
+
+	want := time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	if items := p.items(articles[:1]); !items[0].Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", items[0].Expires, want)
+	}
+}
+
+func TestRetryQueue(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	cfg := config.Default()
+	cfg.News.RetryAttempts = 2
+	earlier := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := earlier.Add(time.Hour)
+
+	p := &newsPipeline{
+		app: &App{cfg: cfg, log: log, state: &state.State{Retry: map[string]state.Retry{
+			"flaky":     {Attempts: 1, LastError: "timeout", Since: earlier},
+			"recovered": {Attempts: 1, LastError: "timeout", Since: earlier},
+			"broken":    {Attempts: 2, LastError: "status 500", Since: earlier},
+			"gone":      {Attempts: 1, LastError: "timeout", Since: earlier},
+		}}},
+		scraper: &news.Scraper{},
+	}
+
+	p.queueRetries()
+	if !p.scraper.Priority["flaky"] || !p.scraper.Priority["recovered"] || p.scraper.Priority["broken"] {
+		t.Fatalf("unexpected priority %v", p.scraper.Priority)
+	}
+
+	p.updateRetries(map[string]string{"flaky": "timeout again", "broken": "timeout", "new": "status 404"}, now)
+	want := map[string]state.Retry{
+		"flaky":  {Attempts: 2, LastError: "timeout again", Since: earlier},
+		"broken": {Attempts: 2, LastError: "status 500", Since: earlier},
+		"new":    {Attempts: 1, LastError: "status 404", Since: now},
+	}
+	if !reflect.DeepEqual(p.app.state.Retry, want) || !p.app.retryChanged {
+		t.Errorf("retry queue = %+v, want %+v", p.app.state.Retry, want)
+	}
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"net/http"
+	_ "net/http/pprof"
+
+	"github.com/sirupsen/logrus"
+)
+
+// startPprof serves the net/http/pprof handlers for the life of the run.
+func startPprof(addr string, log *logrus.Logger) {
+	log.Infof("pprof listening on http://%s/debug/pprof/", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Warnf("pprof server stopped: %v", err)
+		}
+	}()
+}
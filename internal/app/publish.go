@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/publish"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+// newGit returns the sync repository at dir, signed as publish.author and
+// publish.committer.
+func newGit(cfg *config.Config, dir string, log *logrus.Logger) *publish.Git {
+	return &publish.Git{
+		Dir:       dir,
+		Token:     secrets.Get("PAT_TOKEN"),
+		Author:    publish.Identity(cfg.Publish.Author),
+		Committer: publish.Identity(cfg.Publish.Committer),
+		Log:       log,
+	}
+}
+
+// triggeringActor is the person who started a manual GitHub Actions run,
+// with their noreply address so GitHub links the trailer to the account.
+func triggeringActor() (publish.Identity, bool) {
+	if os.Getenv("GITHUB_EVENT_NAME") != "workflow_dispatch" {
+		return publish.Identity{}, false
+	}
+	login := os.Getenv("GITHUB_TRIGGERING_ACTOR")
+	if login == "" {
+		login = os.Getenv("GITHUB_ACTOR")
+	}
+	if login == "" || strings.HasSuffix(login, "[bot]") {
+		return publish.Identity{}, false
+	}
+
+	email := login + "@users.noreply.github.com"
+	if id := os.Getenv("GITHUB_ACTOR_ID"); id != "" && login == os.Getenv("GITHUB_ACTOR") {
+		email = id + "+" + email
+	}
+	return publish.Identity{Name: login, Email: email}, true
+}
+
+// destination sends a committed sync somewhere. Mirrors are sent every
+// page a run could publish, not only the changed ones, so one that missed
+// an upload catches up on a later run.
+type destination struct {
+	name   string
+	mirror bool
+	push   func(ctx context.Context, files []string) error
+}
+
+// destinations builds the configured publish targets, defaulting to the
+// repository's origin. During a freeze git remotes get the freeze branch
+// and mirrors of the live site are left alone.
+func (a *App) destinations(git *publish.Git, frozen bool) []destination {
+	configured := a.cfg.Publish.Destinations
+	if len(configured) == 0 {
+		configured = []config.Destination{{Name: "origin", Type: "git"}}
+	}
+
+	var dests []destination
+	for _, d := range configured {
+		switch d.Type {
+		case "git":
+			remote := publish.Remote{Name: d.Remote, Branch: d.Branch}
+			if d.TokenEnv != "" {
+				remote.Token = secrets.Get(d.TokenEnv)
+			}
+			if frozen {
+				remote.Branch = ""
+			}
+			dests = append(dests, destination{
+				name: nameOr(d.Name, d.Remote, "origin"),
+				push: func(ctx context.Context, files []string) error { return git.Push(ctx, remote) },
+			})
+		case "s3":
+			name := nameOr(d.Name, "s3://"+d.Bucket+"/"+d.Prefix)
+			if frozen {
+				a.log.Infof("freeze window active: skipping %s", name)
+				continue
+			}
+			s3 := &publish.S3{
+				Bucket:       d.Bucket,
+				Prefix:       d.Prefix,
+				Region:       d.Region,
+				Endpoint:     d.Endpoint,
+				AccessKey:    secrets.Get("AWS_ACCESS_KEY_ID"),
+				SecretKey:    secrets.Get("AWS_SECRET_ACCESS_KEY"),
+				SessionToken: secrets.Get("AWS_SESSION_TOKEN"),
+				Dir:          git.Dir,
+				Client:       a.api,
+			}
+			roleARN := d.RoleARN
+			dests = append(dests, destination{name: name, mirror: true, push: func(ctx context.Context, files []string) error {
+				if s3.AccessKey == "" && roleARN != "" {
+					creds, err := secrets.AssumeRoleWithOIDC(ctx, a.api, roleARN)
+					if err != nil {
+						return err
+					}
+					s3.AccessKey, s3.SecretKey, s3.SessionToken = creds.AccessKey, creds.SecretKey, creds.SessionToken
+				}
+				return s3.Upload(ctx, files)
+			}})
+		}
+	}
+	return dests
+}
+
+// split separates git remotes, pushed after a commit, from mirrors.
+func split(dests []destination) (remotes, mirrors []destination) {
+	for _, d := range dests {
+		if d.mirror {
+			mirrors = append(mirrors, d)
+		} else {
+			remotes = append(remotes, d)
+		}
+	}
+	return remotes, mirrors
+}
+
+// publishedFiles lists the pages the pipelines keep, changed or not.
+func publishedFiles(pipelines []pipeline, outputs []*output) []string {
+	var files []string
+	for i, p := range pipelines {
+		out := outputs[i]
+		if out == nil || !out.dataOnly {
+			files = append(files, p.File())
+		}
+		if out != nil {
+			for _, extra := range out.extras {
+				files = append(files, extra.file)
+			}
+		}
+	}
+	return files
+}
+
+func nameOr(names ...string) string {
+	for _, name := range names {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// publishAll pushes files to every destination, retrying each with a
+// doubling delay, and records the outcomes in the report. It returns the
+// names of the destinations that never succeeded.
+func (a *App) publishAll(ctx context.Context, dests []destination, files []string, rep *report.Report) []string {
+	var failed []string
+	for _, d := range dests {
+		result := report.Destination{Name: d.name}
+		delay := a.cfg.Publish.RetryDelay.Duration
+		for attempt := 0; attempt <= a.cfg.Publish.Retries; attempt++ {
+			if attempt > 0 {
+				a.log.Warnf("publishing to %s failed, retrying in %s: %s", d.name, delay, result.Error)
+				select {
+				case <-ctx.Done():
+				case <-time.After(delay):
+				}
+				delay *= 2
+			}
+
+			result.Attempts++
+			err := d.push(ctx, files)
+			if err == nil {
+				result.Pushed, result.Error = true, ""
+				break
+			}
+			result.Error = err.Error()
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		if result.Pushed {
+			a.log.Infof("published to %s", d.name)
+		} else {
+			a.log.Errorf("failed to publish to %s: %s", d.name, result.Error)
+			failed = append(failed, d.name)
+		}
+		rep.Destinations = append(rep.Destinations, result)
+	}
+	return failed
+}
+
+// reportFailedDestinations alerts about destinations left behind by a run
+// that reached at least one other.
+func (a *App) reportFailedDestinations(failed []string) {
+	message := fmt.Sprintf("sync published, but not to %s", strings.Join(failed, ", "))
+	if err := a.notifier.Send("error", message); err != nil {
+		a.log.Warnf("failed to send notification: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/registration"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const (
+	registrationURL  = "https://www.gomotionapp.com/team/cadas/page/registration"
+	registrationHTML = "registration.html"
+)
+
+type registrationPipeline struct {
+	app     *App
+	scraper *registration.Scraper
+}
+
+func (a *App) newRegistrationPipeline() *registrationPipeline {
+	pages := a.cfg.Registration.Pages
+	if len(pages) == 0 {
+		pages = []string{registrationURL}
+	}
+
+	return &registrationPipeline{
+		app: a,
+		scraper: &registration.Scraper{
+			Pages:          pages,
+			BaseURL:        baseURL,
+			RequestTimeout: a.cfg.Fetch.RequestTimeout.Duration,
+			Client:         a.client,
+			Log:            a.log,
+		},
+	}
+}
+
+func (p *registrationPipeline) Name() string    { return "registration" }
+func (p *registrationPipeline) File() string    { return registrationHTML }
+func (p *registrationPipeline) Subject() string { return "registration status" }
+func (p *registrationPipeline) Noun() string    { return "session" }
+
+func (p *registrationPipeline) Build(ctx context.Context, rep *report.Pipeline) (*output, error) {
+	start := time.Now()
+	sessions, err := p.scraper.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep.Timings.Since("fetch", start)
+	start = time.Now()
+
+	if len(sessions) == 0 {
+		rep.Degraded = "no registration sessions found"
+		return nil, nil
+	}
+	rep.Items = len(sessions)
+
+	items := make([]state.Item, 0, len(sessions))
+	records := make([]record, 0, len(sessions))
+	for _, s := range sessions {
+		key := s.URL
+		if key == "" {
+			key = s.Program + "/" + s.Name
+		}
+		items = append(items, state.Item{
+			Key:   key,
+			Title: s.Title(),
+			Date:  s.Dates,
+			Hash:  state.Hash(s.Title(), s.Dates, string(s.Status), strconv.Itoa(s.Spots)),
+		})
+		records = append(records, record{
+			Pipeline: p.Name(),
+			Key:      key,
+			Title:    s.Title(),
+			Date:     s.Dates,
+			URL:      s.URL,
+			Status:   string(s.Status),
+		})
+	}
+
+	restyle := func(s style) string {
+		return render.Localized(s.languages, func(cat *render.Catalog) string {
+			return s.profile.Registration(sessions, cat)
+		}) + "\n"
+	}
+	out := &output{block: restyle(p.app.style(p.File())), items: items, restyle: restyle, records: records}
+	rep.Timings.Since("render", start)
+	return out, nil
+}
+
+func (p *registrationPipeline) Publish(ctx context.Context, rep *report.Pipeline) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/publish"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+)
+
+// runRollback reverts the most recent automated sync commit.
+func runRollback(args []string) {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	root := fs.String("root", "../../", "website repository root")
+	push := fs.Bool("push", false, "push the revert commit")
+	fs.Parse(args)
+
+	log := setupLogger()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	if *push && secrets.Get("PAT_TOKEN") == "" {
+		log.Fatal("missing PAT_TOKEN (environment, PAT_TOKEN_FILE, secrets file or keyring)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.RunDeadline.Duration)
+	defer cancel()
+
+	if err := rollback(ctx, newGit(cfg, *root, log), cfg.StateFile, *push); err != nil {
+		log.Fatal(err)
+	}
+	if !*push {
+		log.Info("revert committed locally; push it or rerun with --push")
+	}
+}
+
+// rollback reverts the last sync commit in git. The state file keeps any
+// changes made after it, since heartbeats commit it on its own.
+func rollback(ctx context.Context, git *publish.Git, stateFile string, push bool) error {
+	commit, err := git.LastSync(commitPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to find sync commit: %w", err)
+	}
+
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	git.Log.Infof("reverting %s %q from %s", commit.Hash.String()[:7], subject, commit.Author.When.Format(time.RFC1123))
+
+	if err := git.Revert(ctx, commit, push, filepath.ToSlash(stateFile)); err != nil {
+		return fmt.Errorf("failed to revert: %w", err)
+	}
+	return nil
+}
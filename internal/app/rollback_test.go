@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dareaquatics/dare-website/internal/publish"
+	git "github.com/go-git/go-git/v5"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRollbackAfterHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.PlainInit(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	g := &publish.Git{Dir: dir, Log: log}
+
+	write := func(name, contents string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(message string, files ...string) {
+		t.Helper()
+		if err := g.Commit(files, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const stateFile = ".synchandler/state.json"
+	write("news.html", "good")
+	write(stateFile, `{"v":1}`)
+	commit("initial", "news.html", stateFile)
+
+	write("news.html", "bad")
+	write(stateFile, `{"v":2}`)
+	commit(commitPrefix+"news articles [skip ci]", "news.html", stateFile)
+
+	write(stateFile, `{"v":3}`)
+	commit(stateCommit, stateFile)
+
+	if err := rollback(context.Background(), g, stateFile, false); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	page, _ := os.ReadFile(filepath.Join(dir, "news.html"))
+	if string(page) != "good" {
+		t.Errorf("news.html = %q, want the content before the sync", page)
+	}
+	st, _ := os.ReadFile(filepath.Join(dir, stateFile))
+	if string(st) != `{"v":3}` {
+		t.Errorf("state = %s, want the heartbeat's state kept", st)
+	}
+
+	repo, _ := git.PlainOpen(dir)
+	head, _ := repo.Head()
+	last, _ := repo.CommitObject(head.Hash())
+	if !strings.HasPrefix(last.Message, `Revert "`+commitPrefix+"news articles") {
+		t.Errorf("last commit = %q, want a revert of the sync commit", last.Message)
+	}
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/schedule"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const (
+	scheduleURL  = "https://www.gomotionapp.com/team/cadas/page/practice-schedule"
+	scheduleHTML = "schedule.html"
+)
+
+type schedulePipeline struct {
+	app     *App
+	scraper *schedule.Scraper
+	// loc is the team's timezone, for the dates changes are shown with.
+	loc *time.Location
+}
+
+func (a *App) newSchedulePipeline() (*schedulePipeline, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone load failed: %w", err)
+	}
+
+	pages := a.cfg.Schedule.Pages
+	if len(pages) == 0 {
+		pages = []string{scheduleURL}
+	}
+
+	return &schedulePipeline{
+		app: a,
+		loc: loc,
+		scraper: &schedule.Scraper{
+			Pages:          pages,
+			RequestTimeout: a.cfg.Fetch.RequestTimeout.Duration,
+			Client:         a.client,
+			Log:            a.log,
+		},
+	}, nil
+}
+
+func (p *schedulePipeline) Name() string    { return "schedule" }
+func (p *schedulePipeline) File() string    { return scheduleHTML }
+func (p *schedulePipeline) Subject() string { return "practice schedule" }
+func (p *schedulePipeline) Noun() string    { return "practice" }
+
+func (p *schedulePipeline) Build(ctx context.Context, rep *report.Pipeline) (*output, error) {
+	start := time.Now()
+	slots, err := p.scraper.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep.Timings.Since("fetch", start)
+	start = time.Now()
+
+	if len(slots) == 0 {
+		rep.Degraded = "no practice times found"
+		return nil, nil
+	}
+	rep.Items = len(slots)
+
+	now := time.Now().In(p.loc)
+	keys := schedule.Keys(slots)
+	p.markMoves(slots, keys, now)
+
+	items := make([]state.Item, 0, len(slots))
+	records := make([]record, 0, len(slots))
+	for i, s := range slots {
+		title := s.Group + " on " + s.Day
+		items = append(items, state.Item{
+			Key:       keys[i],
+			Title:     title,
+			Date:      s.Time,
+			Hash:      hashSlot(s),
+			UpdatedAt: s.Changed,
+		})
+		records = append(records, record{
+			Pipeline: p.Name(),
+			Key:      keys[i],
+			Title:    title,
+			Date:     s.Time,
+			Location: s.Location,
+		})
+	}
+
+	// Highlights fade once the move is old news
+	shown := make([]schedule.Slot, len(slots))
+	copy(shown, slots)
+	days := p.app.cfg.Schedule.HighlightDays
+	for i := range shown {
+		if days == 0 || now.Sub(shown[i].Changed) > time.Duration(days)*24*time.Hour {
+			shown[i].Changed = time.Time{}
+		}
+	}
+
+	restyle := func(s style) string {
+		return render.Localized(s.languages, func(cat *render.Catalog) string {
+			return s.profile.Schedule(shown, cat)
+		}) + "\n"
+	}
+	out := &output{block: restyle(p.app.style(p.File())), items: items, restyle: restyle, records: records}
+	rep.Timings.Since("render", start)
+	return out, nil
+}
+
+// markMoves sets Changed on practices whose time or location differs from
+// what the last sync published, and carries earlier moves forward.
+func (p *schedulePipeline) markMoves(slots []schedule.Slot, keys []string, now time.Time) {
+	previous := map[string]state.Item{}
+	for _, item := range p.app.state.Pipelines[p.Name()] {
+		previous[item.Key] = item
+	}
+	for i := range slots {
+		before, ok := previous[keys[i]]
+		switch {
+		case !ok:
+		case before.Hash != hashSlot(slots[i]):
+			slots[i].Changed = now
+		default:
+			slots[i].Changed = before.UpdatedAt.In(p.loc)
+		}
+	}
+}
+
+func hashSlot(s schedule.Slot) string {
+	return state.Hash(s.Time, s.Location)
+}
+
+func (p *schedulePipeline) Publish(ctx context.Context, rep *report.Pipeline) error {
+	return nil
+}
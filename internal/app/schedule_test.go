@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+	"github.com/dareaquatics/dare-website/internal/schedule"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+func TestMarkMoves(t *testing.T) {
+	earlier := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 9, 8, 0, 0, 0, time.UTC)
+
+	unchanged := schedule.Slot{Group: "Senior", Day: "Mon", Time: "5:30 - 7:30 PM", Location: "Azusa High"}
+	moved := schedule.Slot{Group: "Senior", Day: "Tue", Time: "6:00 - 8:00 PM", Location: "Azusa High"}
+	movedBefore := schedule.Slot{Group: "Senior", Day: "Wed", Time: "5:30 - 7:30 PM", Location: "Memorial Park"}
+	added := schedule.Slot{Group: "Novice", Day: "Sat", Time: "9:00 - 10:00 AM"}
+
+	p := &schedulePipeline{loc: time.UTC, app: &App{cfg: config.Default(), state: &state.State{Pipelines: map[string][]state.Item{
+		"schedule": {
+			{Key: "Senior/Mon", Hash: hashSlot(unchanged)},
+			{Key: "Senior/Tue", Hash: hashSlot(schedule.Slot{Time: "5:30 - 7:30 PM", Location: "Azusa High"})},
+			{Key: "Senior/Wed", Hash: hashSlot(movedBefore), UpdatedAt: earlier},
+		},
+	}}}}
+
+	slots := []schedule.Slot{unchanged, moved, movedBefore, added}
+	p.markMoves(slots, schedule.Keys(slots), now)
+
+	want := []time.Time{{}, now, earlier, {}}
+	for i, s := range slots {
+		if !s.Changed.Equal(want[i]) {
+			t.Errorf("%s on %s: Changed = %v, want %v", s.Group, s.Day, s.Changed, want[i])
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dareaquatics/dare-website/internal/search"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+)
+
+// announce tells search engines about the pages this run pushed. Failures
+// only warn: the site is already updated and crawlers will get there anyway.
+func (a *App) announce(ctx context.Context, urls []string) {
+	cfg := a.cfg.Search
+	if len(urls) == 0 {
+		return
+	}
+
+	key := secrets.Get("INDEXNOW_KEY")
+	if key == "" {
+		key = cfg.IndexNowKey
+	}
+	if key != "" {
+		n := &search.IndexNow{Endpoint: cfg.IndexNowEndpoint, Key: key, KeyLocation: cfg.IndexNowKeyLocation, Client: a.api}
+		if err := n.Submit(ctx, urls); err != nil {
+			a.log.Warnf("indexnow submission failed: %v", err)
+		} else {
+			a.log.Infof("submitted %d urls to indexnow", len(urls))
+		}
+	}
+
+	if cfg.Sitemap == "" {
+		return
+	}
+	for _, endpoint := range cfg.SitemapPings {
+		if err := search.PingSitemap(ctx, a.api, endpoint, cfg.Sitemap); err != nil {
+			a.log.Warnf("sitemap ping to %s failed: %v", endpoint, err)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package app
+
+// Embedded zone database, so the team's timezone loads on Windows runners
+// and in scratch containers that ship no tzdata.
+import _ "time/tzdata"
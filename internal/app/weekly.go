@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/newsletter"
+	"github.com/dareaquatics/dare-website/internal/render"
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/dareaquatics/dare-website/internal/secrets"
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const weeklyHTML = "weekly.html"
+
+// summarizer marks pipelines rendered from what the others fetched; build
+// runs them once those are done.
+type summarizer interface {
+	summarizes()
+}
+
+// weeklyPipeline combines the next week of events with the last week of
+// articles. It fetches nothing itself.
+type weeklyPipeline struct {
+	app      *App
+	news     *newsPipeline
+	calendar *calendarPipeline
+
+	articles []news.Article
+	events   []gocal.Event
+}
+
+func (a *App) newWeeklyPipeline(news *newsPipeline, cal *calendarPipeline) *weeklyPipeline {
+	return &weeklyPipeline{app: a, news: news, calendar: cal}
+}
+
+func (p *weeklyPipeline) Name() string    { return "weekly" }
+func (p *weeklyPipeline) Subject() string { return "weekly summary" }
+func (p *weeklyPipeline) Noun() string    { return "entry" }
+func (p *weeklyPipeline) summarizes()     {}
+
+func (p *weeklyPipeline) File() string {
+	if file := p.app.cfg.Weekly.File; file != "" {
+		return file
+	}
+	return weeklyHTML
+}
+
+func (p *weeklyPipeline) Build(ctx context.Context, rep *report.Pipeline) (*output, error) {
+	// Don't summarize a week out of a source that came back empty
+	if p.news.articles == nil || p.calendar.events == nil {
+		rep.Degraded = "news or calendar came back empty"
+		return nil, nil
+	}
+
+	start := time.Now()
+	now := time.Now().In(p.calendar.fetcher.Location)
+	since, until := now.AddDate(0, 0, -7), now.AddDate(0, 0, 7)
+
+	// Articles not yet in state were first seen now
+	p.articles = nil
+	for _, article := range p.news.articles {
+		born := p.news.born(article)
+		if born.IsZero() || !born.Before(since) {
+			p.articles = append(p.articles, article)
+		}
+	}
+	p.events = nil
+	for _, event := range p.calendar.events {
+		if !calendar.Past(event, now) && event.Start.Before(until) {
+			p.events = append(p.events, event)
+		}
+	}
+	rep.Items = len(p.articles) + len(p.events)
+
+	items := make([]state.Item, 0, rep.Items)
+	for _, event := range p.events {
+		items = append(items, state.Item{
+			Key:     calendar.ID(event),
+			Title:   event.Summary,
+			Date:    event.Start.Format("January 02, 2006"),
+			Hash:    state.Hash(event.Summary, event.Start.String(), event.End.String()),
+			Expires: *event.End,
+		})
+	}
+	for _, article := range p.articles {
+		items = append(items, state.Item{
+			Key:   article.URL,
+			Title: article.Title,
+			Date:  article.Date,
+			Hash:  state.Hash(article.Title, article.Date),
+		})
+	}
+
+	clock := p.app.clock()
+	links := p.articleLinks()
+	anchors := render.EventAnchors(p.calendar.events)
+	restyle := func(s style) string {
+		return render.Localized(s.languages, func(cat *render.Catalog) string {
+			return s.profile.Weekly(p.articles, p.events, links, anchors, clock, cat)
+		}) + "\n"
+	}
+	out := &output{block: restyle(p.app.style(p.File())), items: items, restyle: restyle}
+	rep.Timings.Since("render", start)
+	return out, nil
+}
+
+// articleLinks points each article at its entry on news.html or, once it
+// has moved off, on the archive page. Articles on neither, because there is
+// no archive file or they were purged, aren't linked.
+func (p *weeklyPipeline) articleLinks() map[string]string {
+	links := map[string]string{}
+	if archive := p.app.cfg.News.ArchiveFile; archive != "" {
+		for url, id := range render.ArticleAnchors(p.news.archived) {
+			links[url] = filepath.ToSlash(archive) + "#" + id
+		}
+	}
+	for url, id := range render.ArticleAnchors(p.news.live) {
+		links[url] = p.news.File() + "#" + id
+	}
+	return links
+}
+
+// Publish drafts the summary as a newsletter when weekly.newsletter is on
+// and the page changed this run.
+func (p *weeklyPipeline) Publish(ctx context.Context, rep *report.Pipeline) error {
+	cfg := p.app.cfg.Newsletter
+	if !p.app.cfg.Weekly.Newsletter || cfg.Provider == "" || !rep.Modified {
+		return nil
+	}
+
+	client, err := newsletter.New(cfg.Provider, newsletter.Options{
+		APIKey:   secrets.Get("NEWSLETTER_API_KEY"),
+		ListID:   cfg.ListID,
+		FromName: cfg.FromName,
+		ReplyTo:  cfg.ReplyTo,
+	}, p.app.api)
+	if err != nil {
+		return fmt.Errorf("newsletter: %w", err)
+	}
+
+	clock := p.app.clock()
+	id, err := client.CreateDraft(ctx, newsletter.Draft{
+		Subject:  render.WeeklySubject(time.Now().In(p.calendar.fetcher.Location)),
+		HTML:     render.WeeklyDigest(p.articles, p.events, clock),
+		Markdown: render.WeeklyDigestMarkdown(p.articles, p.events, clock),
+	})
+	if err != nil {
+		return fmt.Errorf("newsletter: %w", err)
+	}
+
+	p.app.log.Infof("created %s weekly draft %s", cfg.Provider, id)
+	return nil
+}
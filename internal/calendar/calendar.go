@@ -0,0 +1,176 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/sirupsen/logrus"
+)
+
+// Fetcher downloads and parses the TeamUnify ICS feed.
+type Fetcher struct {
+	URL      string
+	Location *time.Location
+	// Start and End bound the events kept. The feed spans years, so the
+	// window is applied while parsing rather than afterwards.
+	Start, End time.Time
+	Strip      Policy
+	// Filter drops events kept off the public calendar; nil keeps all.
+	Filter *Filter
+	// RSVP finds signup information in descriptions; nil skips it.
+	RSVP           *RSVPParser
+	Client         *http.Client
+	RequestTimeout time.Duration
+	Log            *logrus.Logger
+}
+
+// Fetch returns the feed's events within the window, converted to
+// Location and ordered by start, and the signup information found in them.
+func (f *Fetcher) Fetch(ctx context.Context) ([]gocal.Event, RSVPs, error) {
+	f.Log.Info("fetching ics data")
+	if f.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", f.URL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request creation failed: %w", err)
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ics fetch failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body := bufio.NewReader(resp.Body)
+	if resp.StatusCode != http.StatusOK || !looksLikeICS(body) {
+		page, _ := io.ReadAll(body)
+		if blocked := fetch.DetectBlock(resp, page); blocked != nil {
+			return nil, nil, blocked
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil, nil, fmt.Errorf("response is not an ics feed")
+	}
+
+	events, rsvps, err := f.parse(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.Log.Infof("processed %d events", len(events))
+	return events, rsvps, nil
+}
+
+// parse reads the feed, keeping events inside the window and converting
+// them to Location.
+func (f *Fetcher) parse(r io.Reader) ([]gocal.Event, RSVPs, error) {
+	parser := gocal.NewParser(r)
+	parser.Start, parser.End = &f.Start, &f.End
+	// All-day dates are days on the team's calendar, not in UTC
+	parser.AllDayEventsTZ = f.Location
+	if err := parser.Parse(); err != nil {
+		return nil, nil, fmt.Errorf("ics parse failed: %w", err)
+	}
+
+	for i := range parser.Events {
+		parser.Events[i].Summary = content.Clean(parser.Events[i].Summary)
+	}
+	// Filter before stripping so rules see the summary as published
+	events := f.Filter.Apply(parser.Events)
+	if dropped := len(parser.Events) - len(events); dropped > 0 {
+		f.Log.Infof("filtered out %d events", dropped)
+	}
+
+	// Read RSVPs before stripping, which may drop the description
+	rsvps := make([]RSVP, len(events))
+	for i, e := range events {
+		rsvps[i] = f.RSVP.Parse(e)
+	}
+	f.Strip.Apply(events)
+	found := RSVPs{}
+	for i := range events {
+		e := &events[i]
+		e.Start = localize(*e.Start, e.RawStart, f.Location)
+		e.End = localize(*e.End, e.RawEnd, f.Location)
+		// Keyed after localizing, as IDs of recurring events use the date
+		if rsvps[i] != (RSVP{}) {
+			found[ID(*e)] = rsvps[i]
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(*events[j].Start)
+	})
+	return events, found, nil
+}
+
+// localize converts t to loc. Floating times (no TZID and no Z) were parsed
+// in the process's local zone, so their wall clock is kept instead.
+func localize(t time.Time, raw gocal.RawDate, loc *time.Location) *time.Time {
+	floating := raw.Value != "" && raw.Params["TZID"] == "" && raw.Params["VALUE"] != "DATE" &&
+		len(raw.Value) > 8 && !strings.HasSuffix(raw.Value, "Z")
+	if floating {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	} else {
+		t = t.In(loc)
+	}
+	return &t
+}
+
+// ID identifies an event across syncs: its UID, plus the occurrence date
+// for instances of a recurring event, which share one UID. Moving a
+// one-off event to another day keeps its id.
+func ID(e gocal.Event) string {
+	if e.Uid == "" {
+		return e.Summary + "/" + e.Start.Format("20060102")
+	}
+	if e.RecurrenceID != "" && len(e.RecurrenceID) >= 8 {
+		return e.Uid + "/" + e.RecurrenceID[:8]
+	}
+	if e.IsRecurring {
+		return e.Uid + "/" + e.Start.Format("20060102")
+	}
+	return e.Uid
+}
+
+// Past reports whether the event is over at now. Both are instants, so the
+// answer does not depend on which zone either is expressed in.
+func Past(e gocal.Event, now time.Time) bool {
+	return e.End.Before(now)
+}
+
+// AllDay reports whether the event covers whole days rather than a span of
+// time. Feeds mark these with date-only values; events built without raw
+// dates count as all-day when they run from midnight to midnight.
+func AllDay(e gocal.Event) bool {
+	if e.RawStart.Value != "" {
+		return e.RawStart.Params["VALUE"] == "DATE" || len(e.RawStart.Value) == 8
+	}
+	return midnight(*e.Start) && (midnight(*e.End) || midnight(e.End.Add(time.Millisecond)))
+}
+
+func midnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// looksLikeICS peeks at the start of the feed without consuming it
+func looksLikeICS(r *bufio.Reader) bool {
+	head, _ := r.Peek(512)
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("BEGIN:VCALENDAR"))
+}
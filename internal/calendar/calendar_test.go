@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var pacific = mustLoadLocation("America/Los_Angeles")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func ics(event string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:e1\r\nDTSTAMP:20250101T000000Z\r\nSUMMARY:Event\r\n" +
+		strings.ReplaceAll(strings.TrimSpace(event), "\n", "\r\n") + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+// TestPastAroundDST checks that events flip from upcoming to past on the
+// team's clock on both sides of the 2025 spring-forward (March 9) and
+// fall-back (November 2) changes, whatever zone the runner is in.
+func TestPastAroundDST(t *testing.T) {
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, pacific)
+	}
+
+	tests := []struct {
+		name  string
+		event string
+		now   time.Time
+		past  bool
+	}{
+		{"all-day on spring-forward day, evening", "DTSTART;VALUE=DATE:20250309\nDTEND;VALUE=DATE:20250310", at(3, 9, 20, 0), false},
+		{"all-day on spring-forward day, after midnight", "DTSTART;VALUE=DATE:20250309\nDTEND;VALUE=DATE:20250310", at(3, 10, 0, 30), true},
+		{"all-day on fall-back day, late evening", "DTSTART;VALUE=DATE:20251102\nDTEND;VALUE=DATE:20251103", at(11, 2, 23, 30), false},
+		{"all-day on fall-back day, after midnight", "DTSTART;VALUE=DATE:20251102\nDTEND;VALUE=DATE:20251103", at(11, 3, 0, 5), true},
+		{"floating time after spring-forward", "DTSTART:20250309T170000\nDTEND:20250309T190000", at(3, 9, 18, 0), false},
+		{"floating time ended", "DTSTART:20250309T170000\nDTEND:20250309T190000", at(3, 9, 19, 1), true},
+		{"floating time after fall-back", "DTSTART:20251102T170000\nDTEND:20251102T190000", at(11, 2, 18, 59), false},
+		{"zoned time across spring-forward", "DTSTART;TZID=America/Los_Angeles:20250309T010000\nDTEND;TZID=America/Los_Angeles:20250309T040000", at(3, 9, 3, 30), false},
+		{"utc time", "DTSTART:20251102T090000Z\nDTEND:20251102T100000Z", at(11, 2, 1, 30), false},
+		{"utc time ended", "DTSTART:20251102T090000Z\nDTEND:20251102T100000Z", at(11, 2, 2, 30), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &Fetcher{
+				Location: pacific,
+				Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+			events, _, err := f.parse(strings.NewReader(ics(tc.event)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("parsed %d events, want 1", len(events))
+			}
+
+			e := events[0]
+			if got := Past(e, tc.now.UTC()); got != tc.past {
+				t.Errorf("Past(%s – %s, %s) = %v, want %v", e.Start, e.End, tc.now, got, tc.past)
+			}
+			if e.Start.Location() != pacific {
+				t.Errorf("start in %s, want %s", e.Start.Location(), pacific)
+			}
+		})
+	}
+}
+
+func TestAllDay(t *testing.T) {
+	tests := []struct {
+		event string
+		want  bool
+	}{
+		{"DTSTART;VALUE=DATE:20250117\nDTEND;VALUE=DATE:20250120", true},
+		{"DTSTART:20250117\nDTEND:20250118", true},
+		{"DTSTART:20250117T000000\nDTEND:20250118T000000", false},
+		{"DTSTART;TZID=America/Los_Angeles:20250121T170000\nDTEND;TZID=America/Los_Angeles:20250121T190000", false},
+	}
+
+	for _, tc := range tests {
+		f := &Fetcher{
+			Location: pacific,
+			Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		events, _, err := f.parse(strings.NewReader(ics(tc.event)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("parsed %d events, want 1", len(events))
+		}
+		if got := AllDay(events[0]); got != tc.want {
+			t.Errorf("AllDay(%q) = %v, want %v", tc.event, got, tc.want)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	f := &Fetcher{
+		Location: pacific,
+		Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, _, err := f.parse(strings.NewReader(ics("DTSTART;TZID=America/Los_Angeles:20250106T170000\nDTEND;TZID=America/Los_Angeles:20250106T190000")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ID(events[0]); got != "e1" {
+		t.Errorf("ID = %q, want e1", got)
+	}
+
+	events, _, err = f.parse(strings.NewReader(ics("DTSTART;TZID=America/Los_Angeles:20250106T170000\nDTEND;TZID=America/Los_Angeles:20250106T190000\nRRULE:FREQ=WEEKLY;COUNT=2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || ID(events[0]) != "e1/20250106" || ID(events[1]) != "e1/20250113" {
+		t.Errorf("recurring ids = %v", events)
+	}
+}
@@ -0,0 +1,111 @@
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/apognu/gocal"
+)
+
+// Rule matches events by their SUMMARY and CATEGORIES. Keywords match
+// case-insensitively anywhere in the summary or a category, Patterns are
+// regular expressions tried against the same text and Categories must
+// equal a category name, ignoring case.
+type Rule struct {
+	Keywords   []string
+	Patterns   []string
+	Categories []string
+}
+
+// Filter decides which events reach the public calendar.
+type Filter struct {
+	include, exclude *matcher
+}
+
+type matcher struct {
+	keywords   []string
+	patterns   []*regexp.Regexp
+	categories map[string]bool
+}
+
+// NewFilter keeps events matching include, or every event when include is
+// empty, unless they match exclude.
+func NewFilter(include, exclude Rule) (*Filter, error) {
+	in, err := newMatcher(include)
+	if err != nil {
+		return nil, fmt.Errorf("calendar include: %w", err)
+	}
+	ex, err := newMatcher(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("calendar exclude: %w", err)
+	}
+	return &Filter{include: in, exclude: ex}, nil
+}
+
+func newMatcher(r Rule) (*matcher, error) {
+	if len(r.Keywords) == 0 && len(r.Patterns) == 0 && len(r.Categories) == 0 {
+		return nil, nil
+	}
+
+	m := &matcher{categories: map[string]bool{}}
+	for _, k := range r.Keywords {
+		m.keywords = append(m.keywords, strings.ToLower(k))
+	}
+	for _, p := range r.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	for _, c := range r.Categories {
+		m.categories[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	return m, nil
+}
+
+func (m *matcher) match(e gocal.Event) bool {
+	texts := append([]string{e.Summary}, e.Categories...)
+	for _, text := range texts {
+		lower := strings.ToLower(text)
+		for _, k := range m.keywords {
+			if strings.Contains(lower, k) {
+				return true
+			}
+		}
+		for _, re := range m.patterns {
+			if re.MatchString(text) {
+				return true
+			}
+		}
+	}
+	for _, c := range e.Categories {
+		if m.categories[strings.ToLower(strings.TrimSpace(c))] {
+			return true
+		}
+	}
+	return false
+}
+
+// Keep reports whether e belongs on the calendar. A nil filter keeps all.
+func (f *Filter) Keep(e gocal.Event) bool {
+	if f == nil {
+		return true
+	}
+	if f.include != nil && !f.include.match(e) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.match(e)
+}
+
+// Apply returns the kept events, reusing the slice.
+func (f *Filter) Apply(events []gocal.Event) []gocal.Event {
+	kept := events[:0]
+	for _, e := range events {
+		if f.Keep(e) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/apognu/gocal"
+)
+
+func TestFilter(t *testing.T) {
+	events := []gocal.Event{
+		{Summary: "Board Meeting"},
+		{Summary: "Senior Practice", Categories: []string{"Practice"}},
+		{Summary: "Winter Championships", Categories: []string{"Meets"}},
+		{Summary: "Volunteer Orientation", Categories: []string{"Admin"}},
+		{Summary: "Coaches Meeting - Closed"},
+	}
+
+	f, err := NewFilter(Rule{}, Rule{
+		Keywords:   []string{"board meeting"},
+		Patterns:   []string{`(?i)\bclosed\b`},
+		Categories: []string{"admin"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSummaries(t, f.Apply(append([]gocal.Event(nil), events...)), "Senior Practice", "Winter Championships")
+
+	f, err = NewFilter(Rule{Categories: []string{"Practice", "Meets"}}, Rule{Keywords: []string{"senior"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSummaries(t, f.Apply(append([]gocal.Event(nil), events...)), "Winter Championships")
+
+	if _, err := NewFilter(Rule{Patterns: []string{"("}}, Rule{}); err == nil {
+		t.Error("NewFilter accepted an invalid pattern")
+	}
+}
+
+func assertSummaries(t *testing.T, events []gocal.Event, want ...string) {
+	t.Helper()
+	if len(events) != len(want) {
+		t.Fatalf("kept %d events, want %v", len(events), want)
+	}
+	for i, e := range events {
+		if e.Summary != want[i] {
+			t.Errorf("event %d = %q, want %q", i, e.Summary, want[i])
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/apognu/gocal"
+)
+
+// RSVP is the signup information an event's description gives.
+type RSVP struct {
+	// Required is set when the description asks for a signup or limits
+	// the number of places. A signup link alone doesn't make it required.
+	Required bool
+	// Capacity is the number of places, or 0 when not stated.
+	Capacity  int
+	SignupURL string
+}
+
+// RSVPRules are the regular expressions that find signup information in
+// an event's description. Capacity patterns capture the number in their
+// first group and Signup patterns match the link itself. Teams word this
+// differently, so each list can be replaced on its own.
+type RSVPRules struct {
+	Required []string
+	Capacity []string
+	Signup   []string
+}
+
+// DefaultRSVPRules cover the usual TeamUnify wording, e.g. "RSVP required
+// by Friday", "limited to 30 swimmers" or a Google Forms link.
+var DefaultRSVPRules = RSVPRules{
+	Required: []string{
+		`(?i)\b(?:rsvp|sign[\s-]?ups?|registration)\s+(?:is\s+)?(?:required|needed|mandatory)\b`,
+		`(?i)\b(?:please\s+)?rsvp\s+(?:by|before|to)\b`,
+		`(?i)\bmust\s+(?:rsvp|sign[\s-]?up|register)\b`,
+	},
+	Capacity: []string{
+		`(?i)\b(?:limited\s+to|capacity(?:\s+of)?:?|max(?:imum)?(?:\s+of)?:?)\s*(\d+)\b`,
+		`(?i)\b(\d+)\s+(?:spots|spaces|seats|places)\b`,
+	},
+	Signup: []string{
+		`(?i)https?://[^\s<>"]*(?:signup|sign-up|rsvp|register|registration|forms\.gle|docs\.google\.com/forms|eventbrite|signupgenius)[^\s<>"]*`,
+	},
+}
+
+// RSVPParser reads RSVP information from events.
+type RSVPParser struct {
+	required, capacity, signup []*regexp.Regexp
+}
+
+// NewRSVPParser compiles rules; an empty list uses the default for it.
+func NewRSVPParser(rules RSVPRules) (*RSVPParser, error) {
+	var p RSVPParser
+	var err error
+	if p.required, err = compile("required", rules.Required, DefaultRSVPRules.Required); err != nil {
+		return nil, err
+	}
+	if p.capacity, err = compile("capacity", rules.Capacity, DefaultRSVPRules.Capacity); err != nil {
+		return nil, err
+	}
+	if p.signup, err = compile("signup", rules.Signup, DefaultRSVPRules.Signup); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func compile(kind string, patterns, defaults []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		patterns = defaults
+	}
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("calendar rsvp %s: invalid pattern %q: %w", kind, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Parse reads the description, falling back to the event's URL for the
+// signup link. A nil parser finds nothing.
+func (p *RSVPParser) Parse(e gocal.Event) RSVP {
+	var r RSVP
+	if p == nil {
+		return r
+	}
+
+	for _, re := range p.required {
+		if re.MatchString(e.Description) {
+			r.Required = true
+			break
+		}
+	}
+	for _, re := range p.capacity {
+		if m := re.FindStringSubmatch(e.Description); len(m) > 1 {
+			if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+				r.Capacity = n
+				break
+			}
+		}
+	}
+	for _, text := range []string{e.Description, e.URL} {
+		for _, re := range p.signup {
+			if link := re.FindString(text); link != "" {
+				r.SignupURL = link
+				break
+			}
+		}
+		if r.SignupURL != "" {
+			break
+		}
+	}
+
+	r.Required = r.Required || r.Capacity > 0
+	return r
+}
+
+// RSVPs holds the signup information found in a feed, keyed by event ID.
+// It is kept beside the events rather than on them, so it survives
+// stripping and reaches every renderer with the events.
+type RSVPs map[string]RSVP
+
+// Of returns the signup information found for e; a nil map has none.
+func (r RSVPs) Of(e gocal.Event) RSVP {
+	return r[ID(e)]
+}
@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apognu/gocal"
+)
+
+func TestRSVPParse(t *testing.T) {
+	p, err := NewRSVPParser(RSVPRules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		want        RSVP
+	}{
+		{"Bring a water bottle.", RSVP{}},
+		{"RSVP required by Friday.", RSVP{Required: true}},
+		{"Limited to 30 swimmers, sign up at https://forms.gle/abc123 today", RSVP{Required: true, Capacity: 30, SignupURL: "https://forms.gle/abc123"}},
+		{"Only 12 spots available.", RSVP{Required: true, Capacity: 12}},
+		{"Meet registration: https://www.gomotionapp.com/team/cadas/page/registration", RSVP{SignupURL: "https://www.gomotionapp.com/team/cadas/page/registration"}},
+	}
+	for _, tc := range tests {
+		if got := p.Parse(gocal.Event{Description: tc.description}); got != tc.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tc.description, got, tc.want)
+		}
+	}
+
+	custom, err := NewRSVPParser(RSVPRules{Required: []string{`(?i)inscripción obligatoria`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := custom.Parse(gocal.Event{Description: "Inscripción obligatoria"}); !got.Required {
+		t.Error("custom required pattern not used")
+	}
+	if _, err := NewRSVPParser(RSVPRules{Capacity: []string{"("}}); err == nil {
+		t.Error("NewRSVPParser accepted an invalid pattern")
+	}
+}
+
+func TestRSVPSurvivesStripping(t *testing.T) {
+	strip, err := NewPolicy([]string{"description", "custom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsvp, _ := NewRSVPParser(RSVPRules{})
+	f := &Fetcher{
+		Location: pacific,
+		Start:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Strip:    strip,
+		RSVP:     rsvp,
+	}
+	events, rsvps, err := f.parse(strings.NewReader(ics("DTSTART;VALUE=DATE:20250309\nDTEND;VALUE=DATE:20250310\nDESCRIPTION:Max 20. RSVP at https://www.signupgenius.com/go/dare")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := RSVP{Required: true, Capacity: 20, SignupURL: "https://www.signupgenius.com/go/dare"}
+	if got := rsvps.Of(events[0]); got != want || events[0].Description != "" || events[0].CustomAttributes != nil {
+		t.Errorf("RSVPs.Of = %+v (description %q), want %+v", got, events[0].Description, want)
+	}
+}
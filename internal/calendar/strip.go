@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apognu/gocal"
+)
+
+// DefaultStrip removes contact details that TeamUnify feeds sometimes carry.
+// VALARM components never survive parsing, so alarms need no entry.
+var DefaultStrip = []string{"organizer", "attendees", "emails", "custom"}
+
+var emailAddress = regexp.MustCompile(`(?i)(?:mailto:)?[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+
+var strippers = map[string]func(e *gocal.Event){
+	"organizer":   func(e *gocal.Event) { e.Organizer = nil },
+	"attendees":   func(e *gocal.Event) { e.Attendees = nil },
+	"attachments": func(e *gocal.Event) { e.Attachments = nil },
+	"description": func(e *gocal.Event) { e.Description = "" },
+	"location":    func(e *gocal.Event) { e.Location, e.Geo = "", nil },
+	"url":         func(e *gocal.Event) { e.URL = "" },
+	"comment":     func(e *gocal.Event) { e.Comment = "" },
+	"custom":      func(e *gocal.Event) { e.CustomAttributes = nil },
+	"emails": func(e *gocal.Event) {
+		e.Summary = redactEmails(e.Summary)
+		e.Description = redactEmails(e.Description)
+		e.Location = redactEmails(e.Location)
+		e.Comment = redactEmails(e.Comment)
+	},
+}
+
+// Policy is the set of properties removed from every event after parsing.
+type Policy []func(e *gocal.Event)
+
+// NewPolicy builds a policy from property names; nil selects DefaultStrip
+// and an empty list keeps everything.
+func NewPolicy(names []string) (Policy, error) {
+	if names == nil {
+		names = DefaultStrip
+	}
+
+	var policy Policy
+	for _, name := range names {
+		strip, ok := strippers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown ics property %q (available: %s)", name, strings.Join(stripNames(), ", "))
+		}
+		policy = append(policy, strip)
+	}
+	return policy, nil
+}
+
+func (p Policy) Apply(events []gocal.Event) {
+	for i := range events {
+		for _, strip := range p {
+			strip(&events[i])
+		}
+	}
+}
+
+func redactEmails(s string) string {
+	return emailAddress.ReplaceAllString(s, "[email removed]")
+}
+
+func stripNames() []string {
+	names := make([]string, 0, len(strippers))
+	for name := range strippers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
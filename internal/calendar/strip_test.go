@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/apognu/gocal"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	policy, err := NewPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []gocal.Event{{
+		Summary:          "Meet",
+		Description:      "Questions? Email coach.dana@example.com or mailto:office@dare.org",
+		Location:         "Alhambra Pool",
+		Organizer:        &gocal.Organizer{Cn: "Dana", Value: "mailto:coach.dana@example.com"},
+		Attendees:        []gocal.Attendee{{Value: "mailto:parent@example.com"}},
+		CustomAttributes: map[string]string{"X-CONTACT": "parent@example.com"},
+	}}
+	policy.Apply(events)
+
+	e := events[0]
+	if want := "Questions? Email [email removed] or [email removed]"; e.Description != want {
+		t.Errorf("Description = %q, want %q", e.Description, want)
+	}
+	if e.Location != "Alhambra Pool" || e.Organizer != nil || e.Attendees != nil || e.CustomAttributes != nil {
+		t.Errorf("unexpected event after stripping: %+v", e)
+	}
+}
+
+func TestPolicyKeepAll(t *testing.T) {
+	policy, err := NewPolicy([]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []gocal.Event{{Description: "a@example.com"}}
+	policy.Apply(events)
+	if events[0].Description != "a@example.com" {
+		t.Errorf("empty policy changed the event: %+v", events[0])
+	}
+}
+
+func TestPolicyUnknown(t *testing.T) {
+	if _, err := NewPolicy([]string{"alarms"}); err == nil {
+		t.Error("NewPolicy accepted an unknown property")
+	}
+}
@@ -0,0 +1,127 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+const header = "# Automated content changes\n\nWritten by synchandler on every sync that changes the site.\n"
+
+// Section is one pipeline's part of a changelog entry.
+type Section struct {
+	Name string
+	// Noun names the items, e.g. "article" or "event".
+	Noun    string
+	Changes state.Changes
+}
+
+// Entry formats the changes of one run as markdown. It returns "" when
+// there is nothing to record.
+func Entry(now time.Time, sections []Section) string {
+	var sb strings.Builder
+	for _, s := range sections {
+		c := s.Changes
+		if c.Empty() {
+			continue
+		}
+
+		if c.Initial {
+			fmt.Fprintf(&sb, "- Started tracking %s\n", count(len(c.Added), s.Noun))
+			continue
+		}
+
+		var parts []string
+		if n := len(c.Added); n > 0 {
+			parts = append(parts, count(n, s.Noun)+" added")
+		}
+		if n := len(c.Updated); n > 0 {
+			parts = append(parts, count(n, s.Noun)+" updated")
+		}
+		if n := len(c.Removed); n > 0 {
+			parts = append(parts, count(n, s.Noun)+" removed")
+		}
+		fmt.Fprintf(&sb, "- %s\n", strings.Join(parts, ", "))
+		writeItems(&sb, "  - ", c)
+	}
+
+	if sb.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n## %s\n\n%s", now.UTC().Format("2006-01-02 15:04 UTC"), sb.String())
+}
+
+// CommitBody lists every changed item per section, for the body of the
+// sync commit.
+func CommitBody(sections []Section) string {
+	var sb strings.Builder
+	for _, s := range sections {
+		c := s.Changes
+		if c.Empty() {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s:\n", s.Name)
+		if c.Initial {
+			fmt.Fprintf(&sb, "- Started tracking %s\n", count(len(c.Added), s.Noun))
+			continue
+		}
+		writeItems(&sb, "- ", c)
+	}
+	return sb.String()
+}
+
+func writeItems(sb *strings.Builder, prefix string, c state.Changes) {
+	for _, group := range []struct {
+		verb  string
+		items []state.Item
+	}{{"Added", c.Added}, {"Updated", c.Updated}, {"Removed", c.Removed}} {
+		for _, item := range group.items {
+			if item.Date != "" {
+				fmt.Fprintf(sb, "%s%s: %s (%s)\n", prefix, group.verb, item.Title, item.Date)
+			} else {
+				fmt.Fprintf(sb, "%s%s: %s\n", prefix, group.verb, item.Title)
+			}
+		}
+	}
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Append adds entry to the end of the changelog at path, creating it with
+// a short header if needed.
+func Append(path, entry string) error {
+	if entry == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("changelog open failed: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("changelog stat failed: %w", err)
+	}
+	if info.Size() == 0 {
+		entry = header + entry
+	}
+
+	if _, err := file.WriteString(entry); err != nil {
+		return fmt.Errorf("changelog write failed: %w", err)
+	}
+	return nil
+}
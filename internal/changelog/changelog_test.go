@@ -0,0 +1,49 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+var sections = []Section{
+	{Name: "news", Noun: "article", Changes: state.Changes{
+		Added:   []state.Item{{Title: "Spring Invitational Results", Date: "April 1, 2024"}},
+		Removed: []state.Item{{Title: "Pool Closure Notice"}},
+	}},
+	{Name: "calendar", Noun: "event", Changes: state.Changes{
+		Initial: true,
+		Added:   []state.Item{{Title: "A"}, {Title: "B"}},
+	}},
+	{Name: "empty", Noun: "thing"},
+}
+
+func TestEntry(t *testing.T) {
+	got := Entry(time.Date(2025, 1, 10, 20, 30, 0, 0, time.UTC), sections)
+	want := `
+## 2025-01-10 20:30 UTC
+
+- 1 article added, 1 article removed
+  - Added: Spring Invitational Results (April 1, 2024)
+  - Removed: Pool Closure Notice
+- Started tracking 2 events
+`
+	if got != want {
+		t.Errorf("Entry() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCommitBody(t *testing.T) {
+	got := CommitBody(sections)
+	want := `news:
+- Added: Spring Invitational Results (April 1, 2024)
+- Removed: Pool Closure Notice
+
+calendar:
+- Started tracking 2 events
+`
+	if got != want {
+		t.Errorf("CommitBody() =\n%s\nwant\n%s", got, want)
+	}
+}
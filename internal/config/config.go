@@ -0,0 +1,552 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+const DefaultPath = "synchandler.json"
+
+var attributeName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.:-]*$`)
+
+type Config struct {
+	// RunDeadline bounds the whole sync, including hooks and the push.
+	RunDeadline Duration `json:"run_deadline"`
+	// StateFile and Changelog live in the website repository and are
+	// committed with the pages. An empty Changelog disables it.
+	StateFile string `json:"state_file"`
+	Changelog string `json:"changelog"`
+
+	Freezes []Freeze `json:"freeze"`
+
+	Watchdog Watchdog `json:"watchdog"`
+
+	Hooks    Hooks    `json:"hooks"`
+	Content  Content  `json:"content"`
+	News     News     `json:"news"`
+	Calendar Calendar `json:"calendar"`
+
+	Registration Registration `json:"registration"`
+	Schedule     Schedule     `json:"schedule"`
+	Weekly       Weekly       `json:"weekly"`
+	Print        Print        `json:"print"`
+	Data         Data         `json:"data"`
+	Render       Render       `json:"render"`
+
+	Retention Retention `json:"retention"`
+
+	Publish    Publish    `json:"publish"`
+	Newsletter Newsletter `json:"newsletter"`
+	Search     Search     `json:"search"`
+	Notify     Notify     `json:"notify"`
+	Fetch      Fetch      `json:"fetch"`
+}
+
+// Fetch controls how politely TeamUnify is crawled.
+type Fetch struct {
+	// Robots is "honor" (default) or "ignore". RobotsFile replaces the
+	// site's robots.txt with a local copy.
+	Robots     string   `json:"robots"`
+	RobotsFile string   `json:"robots_file"`
+	Delay      Duration `json:"delay"`
+	Jitter     Duration `json:"jitter"`
+
+	// UserAgent replaces the default "synchandler/<version> (+contact)"
+	// agent entirely; Contact only swaps the contact URL in it.
+	UserAgent string `json:"user_agent"`
+	Contact   string `json:"contact"`
+	// BrowserFallback retries refused requests with a browser User-Agent.
+	BrowserFallback bool `json:"browser_fallback"`
+
+	DialTimeout         Duration `json:"dial_timeout"`
+	TLSTimeout          Duration `json:"tls_timeout"`
+	ResponseTimeout     Duration `json:"response_timeout"`
+	MaxIdleConnsPerHost int      `json:"max_idle_conns_per_host"`
+	// RequestTimeout caps each request from start to fully read body.
+	RequestTimeout Duration `json:"request_timeout"`
+	// BreakerThreshold is how many consecutive failed requests end the
+	// run early; 0 never does.
+	BreakerThreshold int `json:"breaker_threshold"`
+
+	// CacheDir keeps TeamUnify responses between runs; "" disables the
+	// cache. CacheTTL is how long responses without Cache-Control or
+	// Expires stay fresh.
+	CacheDir string   `json:"cache_dir"`
+	CacheTTL Duration `json:"cache_ttl"`
+}
+
+// Duration reads Go duration strings such as "500ms" or "2s".
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"2s\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Default returns the settings used for anything the config file omits.
+func Default() *Config {
+	return &Config{
+		RunDeadline: Duration{10 * time.Minute},
+		StateFile:   ".synchandler/state.json",
+		Changelog:   "SYNC_CHANGELOG.md",
+		Calendar:    Calendar{DaysAhead: 90, Clock: "12h"},
+		Search:      Search{IndexNowEndpoint: "https://api.indexnow.org/indexnow"},
+		Publish:     Publish{Retries: 2, RetryDelay: Duration{5 * time.Second}},
+		Render:      Render{Analytics: Analytics{Attribute: "data-analytics"}},
+		News:        News{RefetchAfter: Duration{24 * time.Hour}, RetryAttempts: 5},
+		Data:        Data{Dir: "_data"},
+		Schedule:    Schedule{HighlightDays: 14},
+		Watchdog:    Watchdog{MaxAge: Duration{14 * 24 * time.Hour}},
+		Fetch: Fetch{
+			Robots: "honor",
+			Delay:  Duration{500 * time.Millisecond},
+			Jitter: Duration{500 * time.Millisecond},
+
+			DialTimeout:         Duration{5 * time.Second},
+			TLSTimeout:          Duration{10 * time.Second},
+			ResponseTimeout:     Duration{20 * time.Second},
+			MaxIdleConnsPerHost: 5,
+			RequestTimeout:      Duration{30 * time.Second},
+			BreakerThreshold:    5,
+			CacheDir:            defaultCacheDir(),
+			CacheTTL:            Duration{10 * time.Minute},
+		},
+	}
+}
+
+// Watchdog alerts when no sync has succeeded for MaxAge, which catches runs
+// that keep failing or coming back degraded. 0 disables it.
+type Watchdog struct {
+	MaxAge Duration `json:"max_age"`
+}
+
+// Notify configures where run alerts are posted. SYNC_NOTIFY_WEBHOOK
+// overrides WebhookURL.
+type Notify struct {
+	WebhookURL string `json:"webhook_url"`
+	// Format is slack (default), discord or json.
+	Format string `json:"format"`
+}
+
+type News struct {
+	// MaxPages is how many listing pages to follow each run (default 1).
+	MaxPages int `json:"max_pages"`
+
+	// MaxAgeDays keeps older articles off the news page; 0 disables it.
+	// They go to ArchiveFile instead when one is set.
+	MaxAgeDays  int    `json:"max_age_days"`
+	ArchiveFile string `json:"archive_file"`
+	// MaxArticles caps the news page; the rest go to the archive. 0
+	// disables the cap.
+	MaxArticles int `json:"max_articles"`
+	// ArchiveURL is the link to the archive shown under the news block,
+	// defaulting to ArchiveFile.
+	ArchiveURL string `json:"archive_url"`
+	// RefetchAfter is how long an article body fetched earlier is reused
+	// while its listing entry is unchanged. 0 fetches every article.
+	RefetchAfter Duration `json:"refetch_after"`
+	// RetryAttempts bounds how many runs in a row an article that fails
+	// to fetch is fetched first before it is reported and loses priority.
+	RetryAttempts int `json:"retry_attempts"`
+}
+
+// Publish lists where each sync commit goes. Without destinations it is
+// pushed to the repository's origin.
+type Publish struct {
+	Destinations []Destination `json:"destinations"`
+	// Retries is how many more times a failed destination is attempted,
+	// RetryDelay the wait before the first retry (doubling after).
+	Retries    int      `json:"retries"`
+	RetryDelay Duration `json:"retry_delay"`
+
+	// Author signs the sync commits, github-actions[bot] by default, and
+	// Committer, when set, commits them instead of the author.
+	Author    Identity `json:"author"`
+	Committer Identity `json:"committer"`
+	// CoAuthorTrigger credits whoever started a manual workflow run with a
+	// Co-authored-by trailer.
+	CoAuthorTrigger bool `json:"co_author_trigger"`
+}
+
+// Identity is a git name and email.
+type Identity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Destination is a git remote or an S3 bucket mirroring the site.
+type Destination struct {
+	// Name labels the destination in logs and the run report.
+	Name string `json:"name"`
+	// Type is "git" (default) or "s3".
+	Type string `json:"type"`
+
+	// Remote is a configured remote or a URL, defaulting to origin, and
+	// Branch the branch it receives. TokenEnv names the variable holding
+	// its token, PAT_TOKEN by default.
+	Remote   string `json:"remote"`
+	Branch   string `json:"branch"`
+	TokenEnv string `json:"token_env"`
+
+	// Bucket, Region, Prefix and Endpoint locate an S3 mirror. Credentials
+	// come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+	// AWS_SESSION_TOKEN, or without them from assuming RoleARN with the
+	// GitHub Actions OIDC token.
+	Bucket   string `json:"bucket"`
+	Region   string `json:"region"`
+	Prefix   string `json:"prefix"`
+	Endpoint string `json:"endpoint"`
+	RoleARN  string `json:"role_arn"`
+}
+
+// Retention purges old content so the website repository stops growing.
+type Retention struct {
+	// Years drops articles older than this from every generated page and
+	// from the state file; 0 keeps everything.
+	Years int `json:"years"`
+}
+
+// Newsletter drafts a campaign whenever a sync adds articles. The API key
+// is read from NEWSLETTER_API_KEY.
+type Newsletter struct {
+	// Provider is "buttondown" or "mailchimp"; empty disables drafts.
+	Provider string `json:"provider"`
+	// ListID, FromName and ReplyTo are required by Mailchimp.
+	ListID   string `json:"list_id"`
+	FromName string `json:"from_name"`
+	ReplyTo  string `json:"reply_to"`
+}
+
+// Search announces pushed pages to search engines. Nothing is sent
+// without a SiteURL.
+type Search struct {
+	// SiteURL is the public address of the website repository root,
+	// e.g. "https://dareaquatics.com".
+	SiteURL string `json:"site_url"`
+	// IndexNowKey enables IndexNow submissions; INDEXNOW_KEY overrides it.
+	// The key file must be served at IndexNowKeyLocation, or at
+	// SiteURL/<key>.txt when that is empty.
+	IndexNowKey         string `json:"indexnow_key"`
+	IndexNowKeyLocation string `json:"indexnow_key_location"`
+	IndexNowEndpoint    string `json:"indexnow_endpoint"`
+	// Sitemap is pinged at each of SitemapPings, e.g.
+	// "https://www.bing.com/ping".
+	Sitemap      string   `json:"sitemap"`
+	SitemapPings []string `json:"sitemap_pings"`
+}
+
+// Print names the standalone print-friendly pages to generate. Empty
+// paths skip them.
+type Print struct {
+	NewsFile     string `json:"news_file"`
+	CalendarFile string `json:"calendar_file"`
+}
+
+// Data writes the parsed articles and events as data files for static
+// site generators to template themselves.
+type Data struct {
+	// Format is "jekyll" (news.yml, events.yml) or "eleventy" (news.json,
+	// events.json). Empty writes no data files.
+	Format string `json:"format"`
+	// Dir is where the files go, _data by default.
+	Dir string `json:"dir"`
+	// Only leaves news.html and calendar.html alone, for sites whose
+	// templates read the data files instead of the marker blocks.
+	Only bool `json:"only"`
+}
+
+// Render picks the markup written into the site's pages so a redesign can
+// be fed alongside the live site.
+type Render struct {
+	// Profile styles every patched page unless Files names another one for
+	// that path. Empty means "legacy".
+	Profile string            `json:"profile"`
+	Files   map[string]string `json:"files"`
+	// Languages lists the catalogs each block is written in, e.g. ["en",
+	// "es"] for a bilingual block. Empty means English.
+	Languages []string `json:"languages"`
+	// Mirrors write a pipeline's main block into further pages, each with
+	// its own profile.
+	Mirrors []Mirror `json:"mirrors"`
+	// Analytics tags generated links for click tracking.
+	Analytics Analytics `json:"analytics"`
+}
+
+// Analytics sets a data attribute on each kind of generated link. Empty
+// values leave those links untagged.
+type Analytics struct {
+	Attribute      string `json:"attribute"`
+	NewsLink       string `json:"news_link"`
+	ArchiveLink    string `json:"archive_link"`
+	EventButton    string `json:"event_button"`
+	RegisterButton string `json:"register_button"`
+	SignupButton   string `json:"signup_button"`
+}
+
+// Mirror is another page that receives a pipeline's block.
+type Mirror struct {
+	Pipeline string `json:"pipeline"`
+	File     string `json:"file"`
+	Profile  string `json:"profile"`
+	// Languages overrides render.languages for this page.
+	Languages []string `json:"languages"`
+}
+
+// Registration lists the TeamUnify pages whose tables of program sessions
+// are mirrored into registration.html.
+type Registration struct {
+	// Pages defaults to the team's registration page.
+	Pages []string `json:"pages"`
+}
+
+// Schedule lists the TeamUnify pages whose practice tables are mirrored
+// into schedule.html.
+type Schedule struct {
+	// Pages defaults to the team's practice schedule page.
+	Pages []string `json:"pages"`
+	// HighlightDays is how long a practice whose time or location moved
+	// stays highlighted; 0 turns highlighting off.
+	HighlightDays int `json:"highlight_days"`
+}
+
+// Weekly configures the "This week" summary of upcoming events and recent
+// articles.
+type Weekly struct {
+	// File is the page holding the summary block, weekly.html by default.
+	File string `json:"file"`
+	// Newsletter also drafts the summary through the newsletter provider.
+	Newsletter bool `json:"newsletter"`
+}
+
+type Calendar struct {
+	// DaysAhead limits parsing to events ending between now and this many
+	// days out; everything else in the feed is dropped while reading it.
+	DaysAhead int `json:"days_ahead"`
+
+	// Strip lists event properties dropped after parsing, such as
+	// "organizer" or "emails". Unset means calendar.DefaultStrip; an empty
+	// list keeps everything.
+	Strip []string `json:"strip"`
+
+	// Clock is "12h" (default) or "24h" for the times of timed events.
+	Clock string `json:"clock"`
+
+	// Include keeps only matching events when it has any rules; Exclude
+	// then drops matching events, e.g. board meetings.
+	Include EventRule `json:"include"`
+	Exclude EventRule `json:"exclude"`
+
+	// RSVP replaces the patterns that find signup information in event
+	// descriptions.
+	RSVP RSVPRules `json:"rsvp"`
+}
+
+// RSVPRules are regular expressions for RSVP wording; capacity patterns
+// capture the number. An empty list keeps the built-in patterns.
+type RSVPRules struct {
+	Required []string `json:"required"`
+	Capacity []string `json:"capacity"`
+	Signup   []string `json:"signup"`
+}
+
+// EventRule matches events by SUMMARY and CATEGORIES: keywords anywhere
+// in either (ignoring case), regular expressions, or exact category names.
+type EventRule struct {
+	Keywords   []string `json:"keywords"`
+	Patterns   []string `json:"patterns"`
+	Categories []string `json:"categories"`
+}
+
+// Freeze is a window (e.g. a championship weekend) during which the sync
+// must not overwrite the live site. Without a Branch the run only reports
+// pending changes; with one it commits and pushes them there instead.
+type Freeze struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason"`
+	Branch string    `json:"branch"`
+}
+
+// ActiveFreeze returns the freeze window covering now, if any.
+func (c *Config) ActiveFreeze(now time.Time) *Freeze {
+	for i, f := range c.Freezes {
+		if !now.Before(f.Start) && now.Before(f.End) {
+			return &c.Freezes[i]
+		}
+	}
+	return nil
+}
+
+// Hooks lists shell commands run at each stage of a sync. Each command
+// receives the run report as JSON on stdin and in SYNC_REPORT.
+type Hooks struct {
+	PreFetch   []string `json:"pre_fetch"`
+	PostRender []string `json:"post_render"`
+	PreCommit  []string `json:"pre_commit"`
+	PostPush   []string `json:"post_push"`
+}
+
+// Content controls how scraped article bodies are rewritten.
+type Content struct {
+	// Transformers lists pipeline steps by name, in the order they run.
+	// Leaving it empty selects content.DefaultOrder.
+	Transformers []string `json:"transformers"`
+	// Policy is what the sanitize step lets through.
+	Policy ContentPolicy `json:"policy"`
+}
+
+// ContentPolicy starts from a preset ("standard", "strict" or "rich") and
+// overrides the parts that are set.
+type ContentPolicy struct {
+	Preset     string   `json:"preset"`
+	Tags       []string `json:"tags"`
+	Attributes []string `json:"attributes"`
+	Iframes    *bool    `json:"iframes"`
+	Tables     *bool    `json:"tables"`
+	Styles     *bool    `json:"styles"`
+}
+
+// Load reads the config file named by SYNC_CONFIG, falling back to
+// DefaultPath. A missing default file yields Default().
+func Load() (*Config, error) {
+	path := os.Getenv("SYNC_CONFIG")
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath
+	}
+
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("config read failed: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("config parse failed: %w", err)
+	}
+	if cfg.Calendar.DaysAhead <= 0 {
+		return nil, fmt.Errorf("calendar.days_ahead must be positive")
+	}
+	for i, d := range cfg.Publish.Destinations {
+		switch d.Type {
+		case "", "git":
+			cfg.Publish.Destinations[i].Type = "git"
+		case "s3":
+			if d.Bucket == "" || d.Region == "" {
+				return nil, fmt.Errorf("publish destination %q: s3 needs a bucket and a region", d.Name)
+			}
+		default:
+			return nil, fmt.Errorf("publish destination %q: unknown type %q", d.Name, d.Type)
+		}
+	}
+	switch cfg.Data.Format {
+	case "", "jekyll", "eleventy":
+	default:
+		return nil, fmt.Errorf("data.format must be jekyll or eleventy, got %q", cfg.Data.Format)
+	}
+	if cfg.Data.Only && cfg.Data.Format == "" {
+		return nil, fmt.Errorf("data.only needs a data.format")
+	}
+	if cfg.Schedule.HighlightDays < 0 {
+		return nil, fmt.Errorf("schedule.highlight_days must not be negative")
+	}
+	if cfg.News.RetryAttempts < 1 {
+		return nil, fmt.Errorf("news.retry_attempts must be at least 1")
+	}
+	if cfg.News.RefetchAfter.Duration < 0 {
+		return nil, fmt.Errorf("news.refetch_after must not be negative")
+	}
+	if cfg.Watchdog.MaxAge.Duration < 0 {
+		return nil, fmt.Errorf("watchdog.max_age must not be negative")
+	}
+	switch cfg.Fetch.Robots {
+	case "", "honor", "ignore":
+	default:
+		return nil, fmt.Errorf("fetch.robots must be honor or ignore, got %q", cfg.Fetch.Robots)
+	}
+	if cfg.Fetch.BreakerThreshold < 0 {
+		return nil, fmt.Errorf("fetch.breaker_threshold must not be negative")
+	}
+	for field, id := range map[string]Identity{"publish.author": cfg.Publish.Author, "publish.committer": cfg.Publish.Committer} {
+		if (id.Name == "") != (id.Email == "") {
+			return nil, fmt.Errorf("%s needs both a name and an email", field)
+		}
+	}
+	if cfg.Publish.Retries < 0 {
+		return nil, fmt.Errorf("publish.retries must not be negative")
+	}
+	if cfg.Retention.Years < 0 {
+		return nil, fmt.Errorf("retention.years must not be negative")
+	}
+	if cfg.Calendar.Clock != "12h" && cfg.Calendar.Clock != "24h" {
+		return nil, fmt.Errorf("calendar.clock must be 12h or 24h, got %q", cfg.Calendar.Clock)
+	}
+	if a := cfg.Render.Analytics.Attribute; a != "" && !attributeName.MatchString(a) {
+		return nil, fmt.Errorf("render.analytics.attribute %q is not a valid attribute name", a)
+	}
+	for _, m := range cfg.Render.Mirrors {
+		if m.Pipeline == "" || m.File == "" {
+			return nil, fmt.Errorf("render.mirrors entries need a pipeline and a file")
+		}
+	}
+	for _, f := range cfg.Freezes {
+		if !f.End.After(f.Start) {
+			return nil, fmt.Errorf("freeze %q: end must be after start", f.Reason)
+		}
+	}
+
+	// File paths are relative to the config file, not to wherever the
+	// handler later changes directory to
+	base := filepath.Dir(path)
+	cfg.Fetch.RobotsFile = resolvePath(base, cfg.Fetch.RobotsFile)
+	cfg.Fetch.CacheDir = resolvePath(base, cfg.Fetch.CacheDir)
+	return cfg, nil
+}
+
+// defaultCacheDir is synchandler under the user cache directory, or ""
+// (no cache) when the platform has none or the run is in CI, where a
+// cache has to be asked for with fetch.cache_dir.
+func defaultCacheDir() string {
+	if os.Getenv("CI") != "" {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "synchandler")
+}
+
+func resolvePath(base, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if abs, err := filepath.Abs(filepath.Join(base, path)); err == nil {
+		return abs
+	}
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,125 @@
+package content
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Transformer rewrites a parsed article body in place.
+type Transformer interface {
+	Transform(doc *goquery.Document) error
+}
+
+// TransformerFunc adapts a plain function to the Transformer interface.
+type TransformerFunc func(doc *goquery.Document) error
+
+func (f TransformerFunc) Transform(doc *goquery.Document) error {
+	return f(doc)
+}
+
+// Options carries the settings every transformer factory may need.
+type Options struct {
+	BaseURL string
+	// Policy is what the sanitize step keeps; nil means Standard.
+	Policy *Policy
+}
+
+type Factory func(opts Options) Transformer
+
+// DefaultOrder is used when the config does not list transformers.
+// Sanitize runs last so nothing the other steps build escapes the policy.
+var DefaultOrder = []string{"rewrite-images", "flatten-headings", "rewrite-links", "collapse-whitespace", "sanitize"}
+
+var registry = map[string]Factory{}
+
+// Register makes a transformer available to pipelines by name. It is meant
+// to be called from init functions and panics on duplicate names.
+func Register(name string, factory Factory) {
+	if _, exists := registry[name]; exists {
+		panic("content: transformer registered twice: " + name)
+	}
+	registry[name] = factory
+}
+
+// Names returns all registered transformer names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type step struct {
+	name        string
+	transformer Transformer
+}
+
+type Pipeline struct {
+	steps []step
+}
+
+// NewPipeline builds a pipeline running the named transformers in order.
+func NewPipeline(names []string, opts Options) (*Pipeline, error) {
+	if len(names) == 0 {
+		names = DefaultOrder
+	}
+
+	p := &Pipeline{}
+	for _, name := range names {
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown transformer %q (available: %s)", name, strings.Join(Names(), ", "))
+		}
+		p.steps = append(p.steps, step{name: name, transformer: factory(opts)})
+	}
+	return p, nil
+}
+
+// Process runs html through every step and returns the resulting body
+// markup. On a parse failure the input is returned unchanged.
+func (p *Pipeline) Process(html string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html, fmt.Errorf("html parsing failed: %w", err)
+	}
+
+	for _, s := range p.steps {
+		if err := s.transformer.Transform(doc); err != nil {
+			return html, fmt.Errorf("%s transformer failed: %w", s.name, err)
+		}
+	}
+
+	out, err := doc.Find("body").Html()
+	if err != nil {
+		return html, fmt.Errorf("html render failed: %w", err)
+	}
+	return out, nil
+}
+
+// Sanitize strips active content from an HTML fragment under the Standard
+// policy and returns the body markup. Renderers apply it to article bodies
+// so a pipeline configured without "sanitize" still can't put scripts on
+// the site.
+func Sanitize(fragment string) string {
+	return Standard.Sanitize(fragment)
+}
+
+// SetLinkAttr sets an attribute on every link in an HTML fragment.
+func SetLinkAttr(fragment, name, value string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return fragment
+	}
+	doc.Find("a[href]").SetAttr(name, value)
+
+	out, err := doc.Find("body").Html()
+	if err != nil {
+		return fragment
+	}
+	return out
+}
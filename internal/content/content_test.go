@@ -0,0 +1,91 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+const benchBaseURL = "https://www.gomotionapp.com"
+
+// benchArticle approximates a long TeamUnify article: inline styles,
+// relative images, headings, links and <br> soup.
+var benchArticle = strings.Repeat(`<h1 style="color:red">Meet Update</h1>
+<p style="font-size:14px" onclick="x()">Warm-ups start at   7:00am.<br>Bring   two caps.<br/></p>
+<img src="/team/cadas/img/1.jpg" width="600">
+<ul><li>Relays</li>   <li>Distance</li>
+<li>Sprints</li></ul>
+<p>Heat sheets are <a href="/team/cadas/page/results">here</a> and <a href="https://example.com/x">there</a>.</p>
+<script>track()</script>
+`, 50)
+
+func BenchmarkProcess(b *testing.B) {
+	p, err := NewPipeline(nil, Options{BaseURL: benchBaseURL})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchArticle)))
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Process(benchArticle); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransformer isolates each default step so a profile can be
+// pinned on the one that regressed.
+func BenchmarkTransformer(b *testing.B) {
+	for _, name := range DefaultOrder {
+		b.Run(name, func(b *testing.B) {
+			p, err := NewPipeline([]string{name}, Options{BaseURL: benchBaseURL})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := p.Process(benchArticle); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessKeepsEscapedHeadingText(t *testing.T) {
+	p, err := NewPipeline(nil, Options{BaseURL: benchBaseURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Process(`<h2>&lt;script&gt;alert(1)&lt;/script&gt;</h2><img src="/a.jpg&quot; onerror=&quot;x()">`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "<script") || strings.Contains(got, `" onerror`) {
+		t.Errorf("Process = %s", got)
+	}
+	if !strings.Contains(got, `<p class="news-paragraph">&lt;script&gt;alert(1)&lt;/script&gt;</p>`) {
+		t.Errorf("Process dropped the heading text: %s", got)
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	for _, tc := range []struct{ ref, want string }{
+		{"/team/cadas/page/results", benchBaseURL + "/team/cadas/page/results"},
+		{"results?id=2", benchBaseURL + "/results?id=2"},
+		{"https://example.com/x", "https://example.com/x"},
+		{"HTTP://example.com/x", "HTTP://example.com/x"},
+		{"httpdocs/a.pdf", benchBaseURL + "/httpdocs/a.pdf"},
+		{"mailto:coach@example.com", "mailto:coach@example.com"},
+		{"tel:+15555550100", "tel:+15555550100"},
+		{"#schedule", "#schedule"},
+		{"//cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg"},
+		{"", ""},
+	} {
+		if got := absoluteURL(benchBaseURL, tc.ref); got != tc.want {
+			t.Errorf("absoluteURL(%q) = %q, want %q", tc.ref, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package content
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// hardBreak is written as a placeholder so whitespace cleanup can't eat it.
+const (
+	hardBreak    = "\\\n"
+	hardBreakTmp = "\x00br\x00"
+)
+
+var (
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	trailingWS = regexp.MustCompile(`[ \t]+\n`)
+	mdSpecial  = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+)
+
+// Markdown converts an article body to Markdown for outputs that need text
+// rather than HTML (digests, chat notifications, static site generators).
+// It handles headings, paragraphs, lists, links, images, emphasis, code
+// and quotes; anything else contributes only its text.
+func Markdown(body string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("html parsing failed: %w", err)
+	}
+
+	out := markdownChildren(doc)
+	out = trailingWS.ReplaceAllString(out, "\n")
+	out = strings.ReplaceAll(out, hardBreakTmp, hardBreak)
+	out = blankLines.ReplaceAllString(out, "\n\n")
+	return strings.TrimSpace(out), nil
+}
+
+func markdownChildren(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(markdownNode(c))
+	}
+	return sb.String()
+}
+
+func markdownNode(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return mdSpecial.Replace(whitespace.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return markdownChildren(n)
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head:
+		return ""
+	case atom.Br:
+		return hardBreakTmp
+	case atom.Hr:
+		return "\n\n---\n\n"
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " " + inline(n) + "\n\n"
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Table, atom.Tr:
+		return "\n\n" + strings.TrimSpace(markdownChildren(n)) + "\n\n"
+	case atom.Strong, atom.B:
+		return wrap(n, "**")
+	case atom.Em, atom.I:
+		return wrap(n, "*")
+	case atom.Code:
+		return "`" + strings.ReplaceAll(textContent(n), "`", "") + "`"
+	case atom.Pre:
+		return "\n\n```\n" + strings.Trim(textContent(n), "\n") + "\n```\n\n"
+	case atom.A:
+		href := attr(n, "href")
+		text := inline(n)
+		switch {
+		case href == "":
+			return text
+		case text == "":
+			return "<" + href + ">"
+		}
+		return "[" + text + "](" + href + ")"
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" {
+			return ""
+		}
+		return "![" + mdSpecial.Replace(attr(n, "alt")) + "](" + src + ")"
+	case atom.Ul, atom.Ol:
+		return "\n\n" + list(n) + "\n\n"
+	case atom.Blockquote:
+		inner := strings.TrimSpace(blankLines.ReplaceAllString(markdownChildren(n), "\n\n"))
+		return "\n\n> " + strings.ReplaceAll(inner, "\n", "\n> ") + "\n\n"
+	}
+	return markdownChildren(n)
+}
+
+// list renders ul/ol items, indenting continuation lines and nested lists
+// under their item.
+func list(n *html.Node) string {
+	var items []string
+	number := 1
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		inner := strings.TrimSpace(blankLines.ReplaceAllString(markdownChildren(c), "\n\n"))
+		inner = strings.ReplaceAll(inner, "\n\n", "\n")
+		inner = strings.ReplaceAll(inner, "\n", "\n"+strings.Repeat(" ", len(marker)))
+		items = append(items, marker+inner)
+	}
+	return strings.Join(items, "\n")
+}
+
+func inline(n *html.Node) string {
+	return strings.TrimSpace(strings.ReplaceAll(markdownChildren(n), "\n", " "))
+}
+
+func wrap(n *html.Node, marker string) string {
+	text := inline(n)
+	if text == "" {
+		return ""
+	}
+	return marker + text + marker
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package content
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"paragraphs", "<p>One</p><p>Two  words</p>", "One\n\nTwo words"},
+		{"emphasis", "<p><strong>Bold</strong> and <em>it</em> and <b> </b></p>", "**Bold** and *it* and"},
+		{"link", `<p>See <a href="https://example.com/r">results</a>.</p>`, "See [results](https://example.com/r)."},
+		{"bare link", `<a href="https://example.com"></a>`, "<https://example.com>"},
+		{"image", `<img src="https://example.com/a.jpg" alt="Team_photo">`, `![Team\_photo](https://example.com/a.jpg)`},
+		{"heading", "<h2>Meet <i>Info</i></h2><div>Body</div>", "## Meet *Info*\n\nBody"},
+		{"break", "<div>Line one<br>Line two</div>", "Line one\\\nLine two"},
+		{"unordered list", "<ul><li>Relays</li> <li>Distance</li></ul>", "- Relays\n- Distance"},
+		{"ordered nested", "<ol><li>Warm up<ul><li>400 free</li></ul></li><li>Main set</li></ol>", "1. Warm up\n   - 400 free\n2. Main set"},
+		{"escaping", "<p>5*50 [easy] on_1:00</p>", `5\*50 \[easy\] on\_1:00`},
+		{"script", "<p>Hi</p><script>track()</script>", "Hi"},
+		{"quote", "<blockquote><p>Swim fast</p><p>Turn faster</p></blockquote>", "> Swim fast\n>\n> Turn faster"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Markdown(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Markdown(%q) =\n%q\nwant\n%q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
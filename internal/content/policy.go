@@ -0,0 +1,193 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Policy decides which markup survives the sanitize transformer. Scripts,
+// <style> elements, objects, forms, event handlers and URLs with a scheme
+// other than http, https or mailto never do.
+type Policy struct {
+	// Tags, when set, allows only these elements; others are unwrapped so
+	// their text stays.
+	Tags []string
+	// Attributes, when set, allows only these attributes.
+	Attributes []string
+	// Iframes keeps embeds with an https source, Tables keeps tables
+	// instead of flattening rows into paragraphs and Styles keeps inline
+	// style attributes.
+	Iframes bool
+	Tables  bool
+	Styles  bool
+}
+
+// Standard is the policy articles have always been cleaned with.
+var Standard = &Policy{Tables: true, Styles: true}
+
+var policies = map[string]*Policy{
+	"standard": Standard,
+	// strict keeps formatted text and links only
+	"strict": {
+		Tags:       []string{"p", "br", "a", "ul", "ol", "li", "strong", "b", "em", "i", "u", "blockquote"},
+		Attributes: []string{"href", "target", "rel"},
+	},
+	// rich also lets embedded videos and maps through
+	"rich": {Iframes: true, Tables: true, Styles: true},
+}
+
+// LookupPolicy returns a copy of the named policy; "" selects Standard.
+func LookupPolicy(name string) (*Policy, error) {
+	if name == "" {
+		name = "standard"
+	}
+	p, ok := policies[name]
+	if !ok {
+		names := make([]string, 0, len(policies))
+		for n := range policies {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown content policy %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	copied := *p
+	return &copied, nil
+}
+
+// Sanitize applies the policy to an HTML fragment and returns the body
+// markup. A nil policy is Standard.
+func (p *Policy) Sanitize(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+	p.apply(doc)
+
+	out, err := doc.Find("body").Html()
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+	return out
+}
+
+func (p *Policy) apply(doc *goquery.Document) {
+	if p == nil {
+		p = Standard
+	}
+
+	doc.Find("script,style,object,embed,form,base,meta,link").Remove()
+	doc.Find("iframe").Each(func(i int, s *goquery.Selection) {
+		if src, _ := s.Attr("src"); !p.Iframes || !strings.HasPrefix(strings.ToLower(src), "https://") {
+			s.Remove()
+		}
+	})
+	if !p.Tables {
+		flattenTables(doc)
+	}
+
+	if p.Tags != nil {
+		allowed := set(p.Tags)
+		doc.Find("body *").Each(func(i int, s *goquery.Selection) {
+			if allowed[goquery.NodeName(s)] {
+				return
+			}
+			if s.Contents().Length() > 0 {
+				s.Contents().Unwrap()
+			} else {
+				s.Remove()
+			}
+		})
+	}
+
+	var attributes map[string]bool
+	if p.Attributes != nil {
+		attributes = set(p.Attributes)
+	}
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		node := s.Nodes[0]
+		kept := node.Attr[:0]
+		for _, attr := range node.Attr {
+			key := strings.ToLower(attr.Key)
+			switch {
+			case strings.HasPrefix(key, "on"), urlAttributes[key] && !safeURL(attr.Val), key == "srcset" && !safeSrcset(attr.Val):
+			case key == "style" && !p.Styles:
+			case attributes != nil && !attributes[key]:
+			default:
+				kept = append(kept, attr)
+			}
+		}
+		node.Attr = kept
+	})
+}
+
+// urlAttributes are the attributes whose values browsers load or follow.
+var urlAttributes = set([]string{"href", "src", "action", "formaction", "cite", "poster", "background", "longdesc", "xlink:href"})
+
+var safeSchemes = set([]string{"http", "https", "mailto"})
+
+// safeURL reports whether a URL is relative or uses an allowed scheme.
+// Whitespace and control characters are dropped first, as browsers do, so
+// "java\tscript:" is seen as the javascript: URL it is.
+func safeURL(value string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return true
+	}
+	return safeSchemes[strings.ToLower(cleaned[:colon])]
+}
+
+// safeSrcset reports whether every image candidate in a srcset value has a
+// safe URL.
+func safeSrcset(value string) bool {
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 && !safeURL(fields[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenTables turns each table row into a paragraph of its cells.
+func flattenTables(doc *goquery.Document) {
+	// Innermost rows first, so nested tables are flattened before the
+	// cells holding them are copied
+	rows := doc.Find("tr")
+	for i := rows.Length() - 1; i >= 0; i-- {
+		row := rows.Eq(i)
+		cells := row.ChildrenFiltered("td,th").Map(func(i int, cell *goquery.Selection) string {
+			inner, _ := cell.Html()
+			return strings.TrimSpace(inner)
+		})
+		row.ReplaceWithHtml("<p>" + strings.Join(cells, " – ") + "</p>")
+	}
+	doc.Find("caption").Each(func(i int, caption *goquery.Selection) {
+		inner, _ := caption.Html()
+		caption.ReplaceWithHtml("<p>" + inner + "</p>")
+	})
+	doc.Find("colgroup,col").Remove()
+	doc.Find("table,thead,tbody,tfoot").Each(func(i int, s *goquery.Selection) {
+		if s.Contents().Length() > 0 {
+			s.Contents().Unwrap()
+		} else {
+			s.Remove()
+		}
+	})
+}
+
+func set(names []string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, name := range names {
+		m[strings.ToLower(name)] = true
+	}
+	return m
+}
@@ -0,0 +1,92 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+const policyArticle = `<p style="color:red" class="x" onclick="x()">Meet <em>info</em> <span>inside</span></p>` +
+	`<iframe src="https://www.youtube.com/embed/abc"></iframe><iframe src="http://example.com/x"></iframe>` +
+	`<table><tr><th>Event</th><th>Time</th></tr><tr><td>50 Free</td><td>9:00</td></tr></table>` +
+	`<style>p{}</style><script>x()</script>`
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		preset string
+		want   string
+	}{
+		{"standard", `<p style="color:red" class="x">Meet <em>info</em> <span>inside</span></p>` +
+			`<table><tbody><tr><th>Event</th><th>Time</th></tr><tr><td>50 Free</td><td>9:00</td></tr></tbody></table>`},
+		{"strict", `<p>Meet <em>info</em> inside</p><p>Event – Time</p><p>50 Free – 9:00</p>`},
+		{"rich", `<p style="color:red" class="x">Meet <em>info</em> <span>inside</span></p>` +
+			`<iframe src="https://www.youtube.com/embed/abc"></iframe>` +
+			`<table><tbody><tr><th>Event</th><th>Time</th></tr><tr><td>50 Free</td><td>9:00</td></tr></tbody></table>`},
+	}
+
+	for _, tc := range tests {
+		policy, err := LookupPolicy(tc.preset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := policy.Sanitize(policyArticle); got != tc.want {
+			t.Errorf("%s:\n got %s\nwant %s", tc.preset, got, tc.want)
+		}
+	}
+
+	if _, err := LookupPolicy("lenient"); err == nil {
+		t.Error("LookupPolicy accepted an unknown preset")
+	}
+}
+
+func TestSanitizeTransformerUsesPolicy(t *testing.T) {
+	policy, _ := LookupPolicy("standard")
+	policy.Styles = false
+	p, err := NewPipeline([]string{"sanitize"}, Options{Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Process(policyArticle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "style=") || !strings.Contains(got, "<table>") {
+		t.Errorf("Process = %s", got)
+	}
+}
+
+func TestPolicyDropsUnsafeURLs(t *testing.T) {
+	got := Standard.Sanitize(`<a href="java&#9;script:alert(1)">a</a><a href=" JavaScript:x()">b</a>` +
+		`<a href="data:text/html,<script>x()</script>">c</a><img src="vbscript:x">` +
+		`<a href="https://example.com/a:b">d</a><a href="mailto:coach@example.com">e</a><a href="/page?t=1:2">f</a>`)
+	want := `<a>a</a><a>b</a><a>c</a><img/>` +
+		`<a href="https://example.com/a:b">d</a><a href="mailto:coach@example.com">e</a><a href="/page?t=1:2">f</a>`
+	if got != want {
+		t.Errorf("Sanitize:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestPolicyDropsDocumentElements(t *testing.T) {
+	got := Standard.Sanitize(`<p>kept</p><base href="https://evil.example/"><meta http-equiv="refresh" content="0;url=https://evil.example">` +
+		`<link rel="stylesheet" href="https://evil.example/x.css">`)
+	if got != `<p>kept</p>` {
+		t.Errorf("Sanitize = %s, want only the paragraph", got)
+	}
+}
+
+func TestPolicyChecksSrcset(t *testing.T) {
+	for _, tc := range []struct {
+		srcset string
+		kept   bool
+	}{
+		{"/small.jpg 480w, https://example.com/large.jpg 1080w", true},
+		{"photo.jpg, photo@2x.jpg 2x", true},
+		{"/small.jpg 1x, javascript:alert(1) 2x", false},
+		{"data:image/svg+xml;base64,PHN2Zz4= 1x", false},
+	} {
+		got := Standard.Sanitize(`<img src="/a.jpg" srcset="` + tc.srcset + `"/>`)
+		if kept := strings.Contains(got, "srcset="); kept != tc.kept {
+			t.Errorf("srcset %q: kept = %v, want %v (%s)", tc.srcset, kept, tc.kept, got)
+		}
+	}
+}
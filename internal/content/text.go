@@ -0,0 +1,149 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlug = 80
+
+// invisible are format characters TeamUnify's editor leaves in titles. They
+// render as nothing but break string comparison and anchors.
+var invisible = map[rune]bool{
+	'\u00ad': true, // soft hyphen
+	'\u200b': true, // zero width space
+	'\u200c': true, // zero width non-joiner
+	'\u200e': true, // left-to-right mark
+	'\u200f': true, // right-to-left mark
+	'\u2060': true, // word joiner
+	'\ufeff': true, // byte order mark
+}
+
+// emojiWords gives slugs a readable stand-in for emoji common in team posts.
+// Any other symbol only separates words.
+var emojiWords = map[rune]string{
+	'🏊': "swim",
+	'🏆': "trophy",
+	'🥇': "gold",
+	'🥈': "silver",
+	'🥉': "bronze",
+	'🏅': "medal",
+	'🎉': "party",
+	'🎊': "party",
+	'⭐': "star",
+	'🌟': "star",
+	'❤': "heart",
+	'💙': "heart",
+	'📅': "calendar",
+	'📣': "announcement",
+	'📢': "announcement",
+	'⚠': "warning",
+	'🚨': "alert",
+	'✅': "done",
+	'❗': "important",
+	'☀': "sun",
+	'🌊': "wave",
+	'🐬': "dolphin",
+	'🦈': "shark",
+	'🎄': "holiday",
+	'🎃': "halloween",
+	'🔥': "fire",
+	'👏': "applause",
+	'💪': "strong",
+}
+
+// Clean normalizes scraped text such as titles to NFC, drops zero-width and
+// direction characters and collapses whitespace, so the same title always
+// compares, hashes and slugs the same way. Zero width joiners are kept only
+// inside emoji sequences.
+func Clean(s string) string {
+	runes := []rune(norm.NFC.String(s))
+	var sb strings.Builder
+	for i, r := range runes {
+		if invisible[r] {
+			continue
+		}
+		if r == '\u200d' && (i == 0 || i == len(runes)-1 || !symbol(runes[i-1]) || !symbol(runes[i+1])) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// Slug turns a title into a lowercase, hyphen-separated identifier that is
+// safe in HTML ids, URLs and filenames. Accents are folded, known emoji
+// become words and other symbols are dropped. A title with nothing left
+// gets "item".
+func Slug(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	word := func(w string) {
+		if hyphen && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(w)
+		hyphen = false
+	}
+
+	for _, r := range norm.NFKD.String(Clean(title)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word(string(unicode.ToLower(r)))
+		case emojiWords[r] != "":
+			hyphen = true
+			word(emojiWords[r])
+			hyphen = true
+		case r == '\'' || r == '\u2019':
+		default:
+			hyphen = true
+		}
+	}
+
+	slug := sb.String()
+	if len(slug) > maxSlug {
+		slug = strings.TrimRight(truncate(slug, maxSlug), "-")
+	}
+	if slug == "" {
+		return "item"
+	}
+	return slug
+}
+
+// Slugs returns a slug per title, numbering repeats ("meet", "meet-2") so
+// every anchor on a page is unique.
+func Slugs(titles []string) []string {
+	slugs := make([]string, len(titles))
+	seen := map[string]int{}
+	for i, title := range titles {
+		slug := Slug(title)
+		seen[slug]++
+		for n := seen[slug]; n > 1; n++ {
+			candidate := slug + "-" + strconv.Itoa(n)
+			if seen[candidate] == 0 {
+				seen[candidate] = 1
+				slug = candidate
+				break
+			}
+		}
+		slugs[i] = slug
+	}
+	return slugs
+}
+
+func symbol(r rune) bool {
+	return unicode.In(r, unicode.So, unicode.Sk) || r == '\ufe0f'
+}
+
+// truncate cuts s to at most n bytes without splitting a rune.
+func truncate(s string, n int) string {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
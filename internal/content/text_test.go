@@ -0,0 +1,69 @@
+package content
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"decomposed accent", "Cafe\u0301 Social", "Café Social"},
+		{"zero width space", "Meet\u200b Results", "Meet Results"},
+		{"bom and direction marks", "\ufeff\u200eSwim-a-thon\u200f", "Swim-a-thon"},
+		{"stray joiner", "Practice\u200d Update", "Practice Update"},
+		{"emoji sequence kept", "Go \U0001F3CA\u200d♀\ufe0f!", "Go \U0001F3CA\u200d♀\ufe0f!"},
+		{"whitespace", "  Winter \n Championships\t", "Winter Championships"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Clean(tc.in); got != tc.want {
+				t.Errorf("Clean(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Winter Championships 2025", "winter-championships-2025"},
+		{"Coach's Corner: Taper Week!", "coachs-corner-taper-week"},
+		{"Café Social", "cafe-social"},
+		{"Cafe\u0301 Social", "cafe-social"},
+		{"\U0001F3C6 Zones Results \U0001F3C6", "trophy-zones-results-trophy"},
+		{"Go \U0001F3CA\u200d♀\ufe0f Go", "go-swim-go"},
+		{"Meet\u200bResults", "meetresults"},
+		{"\U0001F389\U0001F389", "party-party"},
+		{"✨", "item"},
+		{"", "item"},
+	}
+
+	for _, tc := range tests {
+		if got := Slug(tc.in); got != tc.want {
+			t.Errorf("Slug(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestSlugLength(t *testing.T) {
+	long := ""
+	for i := 0; i < 30; i++ {
+		long += "été "
+	}
+	got := Slug(long)
+	if len(got) > maxSlug || got[len(got)-1] == '-' {
+		t.Errorf("Slug(long) = %q (%d bytes)", got, len(got))
+	}
+}
+
+func TestSlugs(t *testing.T) {
+	got := Slugs([]string{"Meet", "Meet", "Meet 2", "Meet"})
+	want := []string{"meet", "meet-2", "meet-2-2", "meet-3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Slugs = %q, want %q", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+func init() {
+	Register("sanitize", func(opts Options) Transformer { return sanitize(opts.Policy) })
+	Register("rewrite-images", func(opts Options) Transformer { return rewriteImages(opts.BaseURL) })
+	Register("flatten-headings", func(Options) Transformer { return TransformerFunc(flattenHeadings) })
+	Register("rewrite-links", func(opts Options) Transformer { return rewriteLinks(opts.BaseURL) })
+	Register("collapse-whitespace", func(Options) Transformer { return TransformerFunc(collapseWhitespace) })
+}
+
+// absoluteURL resolves ref against baseURL. Absolute URLs (mailto:, tel:
+// and the like included) and fragment-only references are left alone.
+func absoluteURL(baseURL, ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ref
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// Drop active content and whatever else the policy doesn't allow.
+func sanitize(policy *Policy) Transformer {
+	return TransformerFunc(func(doc *goquery.Document) error {
+		policy.apply(doc)
+		return nil
+	})
+}
+
+func rewriteImages(baseURL string) Transformer {
+	return TransformerFunc(func(doc *goquery.Document) error {
+		doc.Find("img").Each(func(i int, s *goquery.Selection) {
+			src, _ := s.Attr("src")
+			s.ReplaceWithHtml(fmt.Sprintf(`<a href="%s" target="_blank">Click to see image</a>`, html.EscapeString(absoluteURL(baseURL, src))))
+		})
+		return nil
+	})
+}
+
+func flattenHeadings(doc *goquery.Document) error {
+	doc.Find("h1,h2,h3,h4,h5,h6").Each(func(i int, s *goquery.Selection) {
+		text := s.Text()
+		s.SetHtml(`<p class="news-paragraph"></p>`)
+		s.Children().SetText(text)
+	})
+	return nil
+}
+
+func rewriteLinks(baseURL string) Transformer {
+	return TransformerFunc(func(doc *goquery.Document) error {
+		doc.Find("a").Each(func(i int, s *goquery.Selection) {
+			href, _ := s.Attr("href")
+			s.SetText("Click here to be redirected to the link")
+			s.SetAttr("href", absoluteURL(baseURL, href))
+			s.SetAttr("target", "_blank")
+		})
+		return nil
+	})
+}
+
+// Collapse runs of whitespace, turn <br> into newlines and drop the gaps
+// between list items.
+func collapseWhitespace(doc *goquery.Document) error {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				c.Data = whitespace.ReplaceAllString(c.Data, " ")
+			}
+			walk(c)
+		}
+	}
+	for _, n := range doc.Nodes {
+		walk(n)
+	}
+
+	doc.Find("br").Each(func(i int, s *goquery.Selection) {
+		s.ReplaceWithNodes(&html.Node{Type: html.TextNode, Data: "\n"})
+	})
+
+	doc.Find("li + li").Each(func(i int, s *goquery.Selection) {
+		for prev := s.Nodes[0].PrevSibling; prev != nil && prev.Type == html.TextNode && strings.TrimSpace(prev.Data) == ""; prev = s.Nodes[0].PrevSibling {
+			prev.Parent.RemoveChild(prev)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,85 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// UnavailableError is returned for every request once the breaker has
+// opened, so the rest of the run fails fast instead of hammering a site
+// that is down.
+type UnavailableError struct {
+	Failures int
+	Last     error
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("unavailable after %d consecutive failures (last: %v)", e.Failures, e.Last)
+}
+
+// Breaker is a RoundTripper that opens after Threshold consecutive
+// failures (transport errors, 429 and 5xx responses). An open breaker
+// stays open for the rest of the run. A zero Threshold disables it.
+type Breaker struct {
+	Next      http.RoundTripper
+	Threshold int
+
+	mu       sync.Mutex
+	failures int
+	last     error
+	open     bool
+}
+
+func (b *Breaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	next := b.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+
+	switch {
+	case err != nil:
+		// Our own deadline or a robots.txt refusal says nothing about the site
+		var disallowed *DisallowedError
+		if req.Context().Err() == nil && !errors.As(err, &disallowed) {
+			b.record(err)
+		}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		b.record(fmt.Errorf("%s returned %s", req.URL, resp.Status))
+	default:
+		b.record(nil)
+	}
+	return resp, err
+}
+
+func (b *Breaker) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return &UnavailableError{Failures: b.failures, Last: b.last}
+	}
+	return nil
+}
+
+// record counts a failure, or resets the count on success.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if !b.open {
+			b.failures, b.last = 0, nil
+		}
+		return
+	}
+	b.failures++
+	b.last = err
+	if b.Threshold > 0 && b.failures >= b.Threshold {
+		b.open = true
+	}
+}
@@ -0,0 +1,54 @@
+package fetch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	hits := 0
+	failing := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Breaker{Threshold: 3}}
+	get := func() error {
+		t.Helper()
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// a success in between resets the count
+	get()
+	get()
+	failing = false
+	get()
+	failing = true
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("request %d failed before the breaker opened: %v", i, err)
+		}
+	}
+
+	err := get()
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("err = %v, want *UnavailableError", err)
+	}
+	if unavailable.Failures != 3 {
+		t.Errorf("failures = %d, want 3", unavailable.Failures)
+	}
+	if hits != 6 {
+		t.Errorf("server hit %d times, want 6", hits)
+	}
+}
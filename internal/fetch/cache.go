@@ -0,0 +1,171 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache is a RoundTripper that keeps successful GET responses in Dir and
+// serves them while they are fresh by their Cache-Control or Expires
+// headers, or for TTL when they carry neither. Stale entries with an ETag
+// or Last-Modified are revalidated rather than fetched again. Responses
+// marked no-store, and interstitials DetectBlock recognizes, are never
+// written.
+type Cache struct {
+	Dir  string
+	TTL  time.Duration
+	Next http.RoundTripper
+}
+
+// cacheEntry is a stored response and when it goes stale.
+type cacheEntry struct {
+	Fixture
+	Expires time.Time `json:"expires"`
+}
+
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := c.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet || req.Header.Get("Authorization") != "" {
+		return next.RoundTrip(req)
+	}
+
+	path := filepath.Join(c.Dir, FixtureName(req.Method, req.URL.String()))
+	entry := c.load(path)
+	now := time.Now()
+	if entry != nil && now.Before(entry.Expires) {
+		return entry.response(req), nil
+	}
+
+	out := req
+	if entry != nil {
+		out = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			out.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		for key, values := range resp.Header {
+			entry.Header[key] = values
+		}
+		if expires, ok := c.expires(entry.Header, now); ok {
+			entry.Expires = expires
+			c.store(path, entry)
+		}
+		return entry.response(req), nil
+	}
+
+	expires, ok := c.expires(resp.Header, now)
+	if resp.StatusCode != http.StatusOK || !ok {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+	// A login or maintenance page served with a 200 must not stand in for
+	// the real one on later runs
+	if DetectBlock(resp, body) != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	entry = &cacheEntry{
+		Fixture: Fixture{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Status: resp.StatusCode,
+			Header: resp.Header,
+			Body:   string(body),
+		},
+		Expires: expires,
+	}
+	c.store(path, entry)
+	return entry.response(req), nil
+}
+
+// expires works out when a response goes stale; false means it must not
+// be stored.
+func (c *Cache) expires(header http.Header, now time.Time) (time.Time, bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(directive)), "=")
+		switch name {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			return now, true
+		case "max-age", "s-maxage":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				return now.Add(time.Duration(seconds) * time.Second), true
+			}
+		}
+	}
+	if value := header.Get("Expires"); value != "" {
+		// An unparseable Expires means already expired
+		expires, _ := http.ParseTime(value)
+		return expires, true
+	}
+	return now.Add(c.TTL), true
+}
+
+// load returns the stored entry for path, treating anything unreadable as
+// a miss.
+func (c *Cache) load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes an entry through a temporary file, so concurrent runs never
+// read half of one. Failures only cost a future hit.
+func (c *Cache) store(path string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
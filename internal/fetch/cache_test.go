@@ -0,0 +1,65 @@
+package fetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	hits, revalidated := map[string]int{}, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidated++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/private":
+			w.Header().Set("Cache-Control", "no-store")
+		case "/maintenance":
+			io.WriteString(w, "<h1>Down for maintenance</h1> ")
+		}
+		io.WriteString(w, "body of "+r.URL.Path)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Cache{Dir: t.TempDir(), TTL: time.Minute}}
+	get := func(path string) string {
+		t.Helper()
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	for _, path := range []string{"/fresh", "/default", "/etag", "/private", "/maintenance"} {
+		for i := 0; i < 2; i++ {
+			if body := get(path); !strings.HasSuffix(body, "body of "+path) {
+				t.Errorf("GET %s #%d = %q", path, i+1, body)
+			}
+		}
+	}
+
+	want := map[string]int{"/fresh": 1, "/default": 1, "/etag": 2, "/private": 2, "/maintenance": 2}
+	for path, n := range want {
+		if hits[path] != n {
+			t.Errorf("%s reached the server %d times, want %d", path, hits[path], n)
+		}
+	}
+	if revalidated != 1 {
+		t.Errorf("revalidated %d times, want 1", revalidated)
+	}
+}
@@ -0,0 +1,69 @@
+package fetch
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/config"
+)
+
+// NewTransport returns the tuned base transport shared by every outgoing
+// request. Each phase has its own timeout rather than one overall budget,
+// so a slow article body doesn't count against connection setup.
+func NewTransport(cfg config.Fetch) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout.Duration,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   cfg.TLSTimeout.Duration,
+		ResponseHeaderTimeout: cfg.ResponseTimeout.Duration,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// NewClient builds the client used for TeamUnify: the shared transport
+// wrapped with robots.txt/pacing, identifying headers, the circuit breaker,
+// the response cache when cacheDir is set and, when enabled through the
+// environment, fixture record/replay.
+func NewClient(cfg config.Fetch, base http.RoundTripper, referer, cacheDir string) (*http.Client, error) {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = UserAgent(cfg.Contact)
+	}
+
+	polite := &Polite{
+		Next:         base,
+		Agent:        userAgent,
+		IgnoreRobots: cfg.Robots == "ignore",
+		RobotsFile:   cfg.RobotsFile,
+		Delay:        cfg.Delay.Duration,
+		Jitter:       cfg.Jitter.Duration,
+	}
+	identity := &Identity{
+		Next:            polite,
+		UserAgent:       userAgent,
+		Referer:         referer,
+		BrowserFallback: cfg.BrowserFallback,
+	}
+
+	// Cache hits skip pacing and don't count towards the breaker
+	var next http.RoundTripper = &Breaker{Next: identity, Threshold: cfg.BreakerThreshold}
+	if cacheDir != "" {
+		next = &Cache{Dir: cacheDir, TTL: cfg.CacheTTL.Duration, Next: next}
+	}
+
+	transport, err := WrapFromEnv(next)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,69 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	BlockedLogin       = "login"
+	BlockedCaptcha     = "captcha"
+	BlockedMaintenance = "maintenance"
+)
+
+// BlockedError reports that TeamUnify served an interstitial (login wall,
+// CAPTCHA or maintenance page) instead of the content we asked for.
+type BlockedError struct {
+	Reason string
+	URL    string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s page served instead of content at %s", e.Reason, e.URL)
+}
+
+var (
+	loginMarkers       = []string{`type="password"`, `type='password'`, "please log in", "please sign in", "sign in to continue", "session has expired"}
+	captchaMarkers     = []string{"g-recaptcha", "h-captcha", "cf-challenge", "challenge-platform", "cf-browser-verification", "are you a robot", "verify you are human"}
+	maintenanceMarkers = []string{"under maintenance", "scheduled maintenance", "temporarily unavailable", "down for maintenance", "be back shortly"}
+)
+
+// DetectBlock classifies a response that didn't have the expected shape.
+// It returns a *BlockedError when the page looks like an interstitial and
+// nil when it can't tell.
+func DetectBlock(resp *http.Response, body []byte) error {
+	finalURL := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+		if isLoginPath(resp.Request.URL) {
+			return &BlockedError{Reason: BlockedLogin, URL: finalURL}
+		}
+	}
+
+	page := strings.ToLower(string(body))
+	switch {
+	case containsAny(page, captchaMarkers):
+		return &BlockedError{Reason: BlockedCaptcha, URL: finalURL}
+	case resp.StatusCode == http.StatusServiceUnavailable || containsAny(page, maintenanceMarkers):
+		return &BlockedError{Reason: BlockedMaintenance, URL: finalURL}
+	case resp.StatusCode == http.StatusUnauthorized || containsAny(page, loginMarkers):
+		return &BlockedError{Reason: BlockedLogin, URL: finalURL}
+	}
+	return nil
+}
+
+func isLoginPath(u *url.URL) bool {
+	path := strings.ToLower(u.Path)
+	return strings.Contains(path, "login") || strings.Contains(path, "signin") || strings.Contains(path, "sign-in")
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
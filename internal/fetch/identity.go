@@ -0,0 +1,69 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	ToolName       = "synchandler"
+	DefaultContact = "https://github.com/dareaquatics/syncHandler"
+
+	// BrowserUserAgent is only sent when browser fallback is enabled and
+	// the identifying agent was refused.
+	BrowserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+)
+
+// Version is reported in the User-Agent; release builds override it with
+// -ldflags "-X github.com/dareaquatics/dare-website/internal/fetch.Version=...".
+var Version = "1.0.0"
+
+// UserAgent builds the identifying agent string, e.g.
+// "synchandler/1.0.0 (+https://github.com/dareaquatics/syncHandler)".
+func UserAgent(contact string) string {
+	if contact == "" {
+		contact = DefaultContact
+	}
+	return fmt.Sprintf("%s/%s (+%s)", ToolName, Version, contact)
+}
+
+// Identity sets the standard request headers on every outgoing request.
+type Identity struct {
+	Next      http.RoundTripper
+	UserAgent string
+	Referer   string
+	// BrowserFallback retries a 403 once with BrowserUserAgent.
+	BrowserFallback bool
+}
+
+func (t *Identity) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(t.withHeaders(req, t.UserAgent))
+	if err != nil || !t.BrowserFallback || resp.StatusCode != http.StatusForbidden || req.Body != nil {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	return next.RoundTrip(t.withHeaders(req, BrowserUserAgent))
+}
+
+// withHeaders clones req as RoundTripper implementations must not modify
+// the caller's request.
+func (t *Identity) withHeaders(req *http.Request, userAgent string) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set("User-Agent", userAgent)
+	if out.Header.Get("Accept") == "" {
+		out.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,text/calendar;q=0.9,*/*;q=0.8")
+	}
+	if out.Header.Get("Accept-Language") == "" {
+		out.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	}
+	if t.Referer != "" && out.Header.Get("Referer") == "" {
+		out.Header.Set("Referer", t.Referer)
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Offline serves every request from Dir. Recorded fixtures (see Recorder)
+// take precedence; otherwise the last path segment of the URL is looked up
+// as a saved page, so .../page/news maps to news.html and .../Events.ics to
+// Events.ics.
+type Offline struct {
+	Dir string
+}
+
+func (o *Offline) RoundTrip(req *http.Request) (*http.Response, error) {
+	fx, err := loadFixture(filepath.Join(o.Dir, FixtureName(req.Method, req.URL.String())))
+	if err == nil {
+		return fx.response(req), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	name := path.Base(req.URL.Path)
+	contentType := "text/calendar"
+	if path.Ext(name) != ".ics" {
+		contentType = "text/html; charset=utf-8"
+		if path.Ext(name) == "" {
+			name += ".html"
+		}
+	}
+
+	body, err := os.ReadFile(filepath.Join(o.Dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no fixture for %s %s (looked for %s)", req.Method, req.URL, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fixture read failed: %w", err)
+	}
+
+	fx = &Fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": []string{contentType}},
+		Body:   string(body),
+	}
+	return fx.response(req), nil
+}
@@ -0,0 +1,180 @@
+package fetch
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DisallowedError is returned for requests robots.txt forbids.
+type DisallowedError struct {
+	URL string
+}
+
+func (e *DisallowedError) Error() string {
+	return fmt.Sprintf("robots.txt disallows %s", e.URL)
+}
+
+// Polite is a RoundTripper that honors robots.txt and spaces out requests
+// to the same host by the larger of Delay and the site's Crawl-delay, plus
+// up to Jitter of random slack.
+type Polite struct {
+	Next  http.RoundTripper
+	Agent string
+	// IgnoreRobots skips robots.txt entirely; RobotsFile replaces the
+	// fetched file with a local one.
+	IgnoreRobots bool
+	RobotsFile   string
+	Delay        time.Duration
+	Jitter       time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	robotsMu sync.Mutex
+	rules    *robotsRules
+	// err is the last failed robots.txt load, returned until retryAt so
+	// one transient failure doesn't decide the rest of the run
+	err     error
+	retryAt time.Time
+
+	paceMu sync.Mutex
+	next   time.Time
+}
+
+// robotsBackoff is how long a failed robots.txt load stands before the
+// file is fetched again.
+var robotsBackoff = 10 * time.Second
+
+func (p *Polite) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := p.host(req.URL.Scheme + "://" + req.URL.Host)
+
+	var rules *robotsRules
+	if !p.IgnoreRobots {
+		var err error
+		if rules, err = p.robots(req, host); err != nil {
+			return nil, err
+		}
+
+		path := req.URL.EscapedPath()
+		if req.URL.RawQuery != "" {
+			path += "?" + req.URL.RawQuery
+		}
+		if !rules.allowed(path) {
+			return nil, &DisallowedError{URL: req.URL.String()}
+		}
+	}
+
+	if err := p.wait(req, host, rules); err != nil {
+		return nil, err
+	}
+	return p.next().RoundTrip(req)
+}
+
+// robots returns the host's rules, loading them on first use. Only a
+// successful load is kept; a failure is retried after robotsBackoff.
+func (p *Polite) robots(req *http.Request, host *hostState) (*robotsRules, error) {
+	host.robotsMu.Lock()
+	defer host.robotsMu.Unlock()
+	if host.rules != nil {
+		return host.rules, nil
+	}
+	if host.err != nil && time.Now().Before(host.retryAt) {
+		return nil, host.err
+	}
+
+	rules, err := p.loadRobots(req)
+	if err != nil {
+		host.err, host.retryAt = err, time.Now().Add(robotsBackoff)
+		return nil, err
+	}
+	host.rules, host.err = rules, nil
+	return rules, nil
+}
+
+func (p *Polite) next() http.RoundTripper {
+	if p.Next == nil {
+		return http.DefaultTransport
+	}
+	return p.Next
+}
+
+func (p *Polite) host(key string) *hostState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hosts == nil {
+		p.hosts = map[string]*hostState{}
+	}
+	if p.hosts[key] == nil {
+		p.hosts[key] = &hostState{}
+	}
+	return p.hosts[key]
+}
+
+func (p *Polite) wait(req *http.Request, host *hostState, rules *robotsRules) error {
+	delay := p.Delay
+	if rules != nil && rules.crawlDelay > delay {
+		delay = rules.crawlDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+
+	host.paceMu.Lock()
+	defer host.paceMu.Unlock()
+
+	if wait := time.Until(host.next); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+	host.next = time.Now().Add(delay)
+	return nil
+}
+
+// loadRobots follows RFC 9309: a missing file (4xx) allows everything,
+// while an unreachable one (5xx or network error) allows nothing.
+func (p *Polite) loadRobots(orig *http.Request) (*robotsRules, error) {
+	if p.RobotsFile != "" {
+		f, err := os.Open(p.RobotsFile)
+		if err != nil {
+			return nil, fmt.Errorf("robots override read failed: %w", err)
+		}
+		defer f.Close()
+		return parseRobots(f, p.Agent), nil
+	}
+
+	robotsURL := orig.URL.Scheme + "://" + orig.URL.Host + "/robots.txt"
+	req, err := http.NewRequestWithContext(orig.Context(), "GET", robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("robots request creation failed: %w", err)
+	}
+	req.Header.Set("User-Agent", orig.Header.Get("User-Agent"))
+
+	client := &http.Client{Transport: p.next()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("robots.txt unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("robots.txt unreachable: unexpected status code: %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		return &robotsRules{}, nil
+	case !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/") && resp.Header.Get("Content-Type") != "":
+		return &robotsRules{}, nil
+	}
+	return parseRobots(resp.Body, p.Agent), nil
+}
@@ -0,0 +1,90 @@
+package fetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleRobots = `
+# sample
+User-agent: BadBot
+Disallow: /
+
+User-agent: *
+Crawl-delay: 2
+Disallow: /team/*/controller/
+Disallow: /*.pdf$
+Allow: /team/cadas/controller/public
+Disallow: /rest/
+`
+
+func TestRobotsRules(t *testing.T) {
+	rules := parseRobots(strings.NewReader(sampleRobots), "synchandler/1.0")
+
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("crawl delay = %s, want 2s", rules.crawlDelay)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/team/cadas/page/news", true},
+		{"/team/cadas/controller/cms/admin", false},
+		{"/team/cadas/controller/public/x", true},
+		{"/files/report.pdf", false},
+		{"/files/report.pdf?x=1", true},
+		{"/rest/ics/system/5/Events.ics", false},
+		{"/robots.txt", true},
+	}
+	for _, tt := range tests {
+		if got := rules.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if parseRobots(strings.NewReader(sampleRobots), "Mozilla BadBot/2").allowed("/team/cadas/page/news") {
+		t.Error("specific group should disallow everything for BadBot")
+	}
+}
+
+func TestPoliteRetriesFailedRobots(t *testing.T) {
+	robotsStatus := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(robotsStatus)
+			return
+		}
+		io.WriteString(w, "page")
+	}))
+	defer srv.Close()
+
+	defer func(backoff time.Duration) { robotsBackoff = backoff }(robotsBackoff)
+	robotsBackoff = 200 * time.Millisecond
+	client := &http.Client{Transport: &Polite{Next: srv.Client().Transport}}
+	get := func() error {
+		t.Helper()
+		resp, err := client.Get(srv.URL + "/page")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err == nil {
+		t.Fatal("unreachable robots.txt allowed the request")
+	}
+	robotsStatus = http.StatusOK
+	if err := get(); err == nil {
+		t.Fatal("robots.txt fetched again before the backoff")
+	}
+
+	time.Sleep(robotsBackoff)
+	if err := get(); err != nil {
+		t.Fatalf("robots.txt not retried: %v", err)
+	}
+}
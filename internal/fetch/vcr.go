@@ -0,0 +1,169 @@
+package fetch
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type Mode string
+
+const (
+	ModeLive   Mode = ""
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Fixture is one recorded HTTP exchange as stored on disk.
+type Fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Recorder is a RoundTripper that saves responses to Dir (ModeRecord) or
+// serves them from Dir without touching the network (ModeReplay).
+type Recorder struct {
+	Mode Mode
+	Dir  string
+	Next http.RoundTripper
+}
+
+// WrapFromEnv wraps next in a Recorder when SYNC_HTTP_MODE is set to record
+// or replay, using SYNC_HTTP_FIXTURES as the fixture directory.
+func WrapFromEnv(next http.RoundTripper) (http.RoundTripper, error) {
+	mode := Mode(os.Getenv("SYNC_HTTP_MODE"))
+	switch mode {
+	case ModeLive:
+		return next, nil
+	case ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown SYNC_HTTP_MODE %q", mode)
+	}
+
+	dir := os.Getenv("SYNC_HTTP_FIXTURES")
+	if dir == "" {
+		return nil, fmt.Errorf("SYNC_HTTP_FIXTURES is required in %s mode", mode)
+	}
+
+	// Resolve now so a later chdir doesn't move the fixtures
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fixture path failed: %w", err)
+	}
+	return &Recorder{Mode: mode, Dir: dir, Next: next}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(r.Dir, FixtureName(req.Method, req.URL.String()))
+
+	if r.Mode == ModeReplay {
+		fx, err := loadFixture(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no fixture for %s %s", req.Method, req.URL)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return fx.response(req), nil
+	}
+
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil || r.Mode != ModeRecord {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+
+	fx := Fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   string(body),
+	}
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("fixture encode failed: %w", err)
+	}
+
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("fixture dir create failed: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("fixture write failed: %w", err)
+	}
+
+	return fx.response(req), nil
+}
+
+func loadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fx Fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("fixture parse failed: %w", err)
+	}
+	return &fx, nil
+}
+
+func (fx Fixture) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fx.Status, http.StatusText(fx.Status)),
+		StatusCode:    fx.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fx.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(fx.Body))),
+		ContentLength: int64(len(fx.Body)),
+		Request:       req,
+	}
+}
+
+// FixtureName maps a request to a readable file name, e.g.
+// GET_www.gomotionapp.com_team_cadas_page_news.json. Long names and query
+// strings get a short hash suffix to stay unique.
+func FixtureName(method, rawURL string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+	query := ""
+	if i := strings.IndexByte(name, '?'); i >= 0 {
+		name, query = name[:i], name[i:]
+	}
+	name = strings.Trim(unsafeChars.ReplaceAllString(name, "_"), "_")
+
+	if query != "" || len(name) > 100 {
+		sum := sha1.Sum([]byte(rawURL))
+		if len(name) > 100 {
+			name = name[:100]
+		}
+		name += "_" + hex.EncodeToString(sum[:4])
+	}
+	return method + "_" + name + ".json"
+}
@@ -0,0 +1,45 @@
+package fetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "text/calendar")
+		io.WriteString(w, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	get := func(mode Mode) (*http.Response, string) {
+		t.Helper()
+		client := &http.Client{Transport: &Recorder{Mode: mode, Dir: dir}}
+		resp, err := client.Get(srv.URL + "/Events.ics?key=abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	_, recorded := get(ModeRecord)
+	srv.Close()
+	resp, replayed := get(ModeReplay)
+
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1", hits)
+	}
+	if replayed != recorded {
+		t.Errorf("replayed body %q, want %q", replayed, recorded)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/calendar" {
+		t.Errorf("unexpected replayed response: %d %v", resp.StatusCode, resp.Header)
+	}
+}
@@ -0,0 +1,253 @@
+package ghost
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	adminURL string
+	keyID    string
+	secret   []byte
+	http     *http.Client
+}
+
+type Post struct {
+	ID           string   `json:"id,omitempty"`
+	Title        string   `json:"title"`
+	HTML         string   `json:"html,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Authors      []string `json:"authors,omitempty"`
+	CanonicalURL string   `json:"canonical_url,omitempty"`
+	PublishedAt  string   `json:"published_at,omitempty"`
+	UpdatedAt    string   `json:"updated_at,omitempty"`
+	// CodeinjectionFoot carries the sync marker; UpsertPost sets it.
+	CodeinjectionFoot string `json:"codeinjection_foot,omitempty"`
+}
+
+// syncMarker prefixes the content hash that UpsertPost leaves in a post's
+// footer code injection, an HTML comment that never shows on the page.
+const syncMarker = "<!-- synchandler:"
+
+// hash fingerprints everything UpsertPost sends for the post.
+func (p Post) hash() string {
+	p.ID, p.UpdatedAt, p.CodeinjectionFoot = "", "", ""
+	payload, _ := json.Marshal(p)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// syncedHash is the hash an earlier UpsertPost recorded, if any.
+func (p Post) syncedHash() string {
+	_, rest, ok := strings.Cut(p.CodeinjectionFoot, syncMarker)
+	if !ok {
+		return ""
+	}
+	hash, _, _ := strings.Cut(rest, " -->")
+	return hash
+}
+
+// statusError is a non-2xx Admin API response.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d: %s", e.code, e.msg)
+}
+
+type postsEnvelope struct {
+	Posts []Post `json:"posts"`
+}
+
+// New creates an Admin API client from the site URL and an admin key in
+// Ghost's "id:secret" format.
+func New(adminURL, adminKey string, httpClient *http.Client) (*Client, error) {
+	id, secretHex, ok := strings.Cut(adminKey, ":")
+	if !ok {
+		return nil, fmt.Errorf("admin key must be in id:secret format")
+	}
+
+	secret, err := hex.DecodeString(secretHex)
+	if err != nil {
+		return nil, fmt.Errorf("admin key decode failed: %w", err)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		adminURL: strings.TrimRight(adminURL, "/") + "/ghost/api/admin",
+		keyID:    id,
+		secret:   secret,
+		http:     httpClient,
+	}, nil
+}
+
+// UpsertPost creates the post, or updates the existing one that shares its
+// canonical URL so repeated syncs don't produce duplicates. Posts are
+// marked with a hash of what was sent, and one whose hash is unchanged is
+// left alone, since Ghost's stored HTML never matches ours byte for byte.
+func (c *Client) UpsertPost(post Post) (created bool, err error) {
+	hash := post.hash()
+	post.ID, post.UpdatedAt = "", ""
+	post.CodeinjectionFoot = syncMarker + hash + " -->"
+
+	existing, err := c.findByCanonicalURL(post.CanonicalURL)
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		if err := c.do("POST", "/posts/?source=html", postsEnvelope{Posts: []Post{post}}, nil); err != nil {
+			return false, fmt.Errorf("post create failed: %w", err)
+		}
+		return true, nil
+	}
+
+	if existing.syncedHash() == hash {
+		return false, nil
+	}
+
+	err = c.update(existing, post)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusConflict {
+		// Edited in Ghost since the lookup; retry once on its new updated_at
+		if existing, err = c.findByCanonicalURL(post.CanonicalURL); err != nil {
+			return false, err
+		}
+		if existing == nil {
+			return false, fmt.Errorf("post update failed: %s disappeared", post.CanonicalURL)
+		}
+		err = c.update(existing, post)
+	}
+	if err != nil {
+		return false, fmt.Errorf("post update failed: %w", err)
+	}
+	return false, nil
+}
+
+// update overwrites existing with post. Ghost rejects the write with 409
+// Conflict unless updated_at matches the stored post.
+func (c *Client) update(existing *Post, post Post) error {
+	post.UpdatedAt = existing.UpdatedAt
+	return c.do("PUT", "/posts/"+existing.ID+"/?source=html", postsEnvelope{Posts: []Post{post}}, nil)
+}
+
+func (c *Client) findByCanonicalURL(canonicalURL string) (*Post, error) {
+	if canonicalURL == "" {
+		return nil, nil
+	}
+
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf("canonical_url:'%s'", strings.ReplaceAll(canonicalURL, "'", `\'`)))
+	query.Set("formats", "html")
+	query.Set("limit", "1")
+
+	var result struct {
+		Posts []struct {
+			ID           string `json:"id"`
+			Title        string `json:"title"`
+			HTML         string `json:"html"`
+			CanonicalURL string `json:"canonical_url"`
+			UpdatedAt    string `json:"updated_at"`
+			// null, which leaves it empty, when nothing is injected
+			CodeinjectionFoot string `json:"codeinjection_foot"`
+		} `json:"posts"`
+	}
+	if err := c.do("GET", "/posts/?"+query.Encode(), nil, &result); err != nil {
+		return nil, fmt.Errorf("post lookup failed: %w", err)
+	}
+
+	if len(result.Posts) == 0 {
+		return nil, nil
+	}
+
+	p := result.Posts[0]
+	return &Post{ID: p.ID, Title: p.Title, HTML: p.HTML, CanonicalURL: p.CanonicalURL, UpdatedAt: p.UpdatedAt, CodeinjectionFoot: p.CodeinjectionFoot}, nil
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("json encode failed: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, c.adminURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+
+	token, err := c.token(time.Now())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Ghost "+token)
+	req.Header.Set("Accept-Version", "v5.0")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{code: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("json decode failed: %w", err)
+	}
+	return nil
+}
+
+// token signs a short-lived admin JWT as described in Ghost's Admin API docs.
+func (c *Client) token(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT", "kid": c.keyID})
+	if err != nil {
+		return "", fmt.Errorf("token header encode failed: %w", err)
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+		"aud": "/admin/",
+	})
+	if err != nil {
+		return "", fmt.Errorf("token claims encode failed: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil)), nil
+}
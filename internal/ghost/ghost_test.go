@@ -0,0 +1,179 @@
+package ghost
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeGhost stores posts by canonical URL and enforces updated_at on
+// updates the way the Admin API does.
+type fakeGhost struct {
+	posts   map[string]*Post
+	writes  []string
+	version int
+	// onLookup runs after each lookup, e.g. to simulate an edit in Ghost.
+	onLookup func()
+}
+
+func (g *fakeGhost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Ghost ") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		canonical := strings.TrimSuffix(strings.TrimPrefix(r.URL.Query().Get("filter"), "canonical_url:'"), "'")
+		var found []Post
+		if p, ok := g.posts[canonical]; ok {
+			found = append(found, *p)
+		}
+		json.NewEncoder(w).Encode(postsEnvelope{Posts: found})
+		if g.onLookup != nil {
+			g.onLookup()
+		}
+		return
+	}
+
+	var body postsEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Posts) != 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	post := body.Posts[0]
+	g.writes = append(g.writes, r.Method)
+
+	switch r.Method {
+	case "POST":
+		post.ID = fmt.Sprintf("post%d", len(g.posts)+1)
+	case "PUT":
+		existing := g.posts[post.CanonicalURL]
+		if existing == nil || !strings.Contains(r.URL.Path, "/posts/"+existing.ID+"/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if post.UpdatedAt != existing.UpdatedAt {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"errors":[{"type":"UpdateCollisionError"}]}`)
+			return
+		}
+		post.ID = existing.ID
+	}
+	// Ghost rewrites the HTML it stores, so it never comes back verbatim
+	post.HTML = "<!--kg-card-begin: html-->" + post.HTML + "<!--kg-card-end: html-->"
+	g.bump(&post)
+	g.posts[post.CanonicalURL] = &post
+	json.NewEncoder(w).Encode(postsEnvelope{Posts: []Post{post}})
+}
+
+func (g *fakeGhost) bump(p *Post) {
+	g.version++
+	p.UpdatedAt = fmt.Sprintf("2024-05-01T00:00:%02d.000Z", g.version)
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeGhost) {
+	t.Helper()
+	g := &fakeGhost{posts: map[string]*Post{}}
+	srv := httptest.NewServer(g)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "abc123:"+strings.Repeat("0f", 32), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, g
+}
+
+var testPost = Post{
+	Title:        "Spring Invitational Results",
+	HTML:         "<p>Results</p>",
+	Status:       "published",
+	Tags:         []string{"News"},
+	CanonicalURL: "https://www.gomotionapp.com/team/cadas/page/news/1001",
+}
+
+func TestUpsertPostCreatesThenSkipsUnchanged(t *testing.T) {
+	c, g := newTestClient(t)
+
+	created, err := c.UpsertPost(testPost)
+	if err != nil || !created {
+		t.Fatalf("first upsert: created=%v err=%v", created, err)
+	}
+	created, err = c.UpsertPost(testPost)
+	if err != nil || created {
+		t.Fatalf("second upsert: created=%v err=%v", created, err)
+	}
+
+	if strings.Join(g.writes, ",") != "POST" {
+		t.Errorf("writes = %v, want only the create", g.writes)
+	}
+	if foot := g.posts[testPost.CanonicalURL].CodeinjectionFoot; !strings.HasPrefix(foot, syncMarker) {
+		t.Errorf("code injection = %q, want the sync marker", foot)
+	}
+}
+
+func TestUpsertPostUpdatesChanged(t *testing.T) {
+	c, g := newTestClient(t)
+	if _, err := c.UpsertPost(testPost); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := testPost
+	edited.HTML = "<p>Corrected results</p>"
+	created, err := c.UpsertPost(edited)
+	if err != nil || created {
+		t.Fatalf("upsert: created=%v err=%v", created, err)
+	}
+
+	if strings.Join(g.writes, ",") != "POST,PUT" {
+		t.Errorf("writes = %v, want a create and an update", g.writes)
+	}
+	if got := g.posts[testPost.CanonicalURL]; !strings.Contains(got.HTML, "Corrected") || got.ID != "post1" {
+		t.Errorf("stored post = %+v", got)
+	}
+}
+
+func TestUpsertPostRetriesUpdateCollision(t *testing.T) {
+	c, g := newTestClient(t)
+	if _, err := c.UpsertPost(testPost); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone saves the post in Ghost right after the first lookup
+	edits := 0
+	g.onLookup = func() {
+		if edits == 0 {
+			g.bump(g.posts[testPost.CanonicalURL])
+		}
+		edits++
+	}
+
+	edited := testPost
+	edited.Title = "Spring Invitational Final Results"
+	if _, err := c.UpsertPost(edited); err != nil {
+		t.Fatal(err)
+	}
+
+	if edits != 2 || g.posts[testPost.CanonicalURL].Title != edited.Title {
+		t.Errorf("lookups=%d stored=%+v", edits, g.posts[testPost.CanonicalURL])
+	}
+}
+
+func TestUpsertPostGivesUpAfterSecondCollision(t *testing.T) {
+	c, g := newTestClient(t)
+	if _, err := c.UpsertPost(testPost); err != nil {
+		t.Fatal(err)
+	}
+
+	g.onLookup = func() { g.bump(g.posts[testPost.CanonicalURL]) }
+
+	edited := testPost
+	edited.HTML = "<p>Corrected results</p>"
+	if _, err := c.UpsertPost(edited); err == nil || !strings.Contains(err.Error(), "409") {
+		t.Errorf("expected a 409 error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/dareaquatics/dare-website/internal/report"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PreFetch   = "pre-fetch"
+	PostRender = "post-render"
+	PreCommit  = "pre-commit"
+	PostPush   = "post-push"
+)
+
+// Run executes the commands configured for stage in order, stopping at the
+// first failure.
+func Run(ctx context.Context, stage string, commands []string, rep *report.Report, log *logrus.Logger) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	rep.Stage = stage
+	payload, err := rep.JSON()
+	if err != nil {
+		return fmt.Errorf("report encode failed: %w", err)
+	}
+
+	for _, command := range commands {
+		log.Infof("running %s hook: %s", stage, command)
+		cmd := shellCommand(ctx, command)
+		cmd.Stdin = bytes.NewReader(payload)
+		// Output goes through the logger so secrets are redacted from it too
+		entry := log.WithField("hook", stage)
+		stdout := &lineWriter{log: entry.Info}
+		stderr := &lineWriter{log: entry.Warn}
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+		cmd.Env = append(os.Environ(),
+			"SYNC_STAGE="+stage,
+			"SYNC_HANDLER="+rep.Handlers(),
+			"SYNC_OUTPUT_FILES="+strings.Join(rep.OutputFiles(), " "),
+			"SYNC_MODIFIED="+strconv.FormatBool(rep.Modified),
+			"SYNC_REPORT="+string(payload),
+		)
+
+		err := cmd.Run()
+		stdout.Flush()
+		stderr.Flush()
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, command, err)
+		}
+	}
+	return nil
+}
+
+// lineWriter logs each line written to it.
+type lineWriter struct {
+	log func(args ...interface{})
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(strings.TrimRight(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush logs a last line that didn't end in a newline.
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.log(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// shellCommand runs a hook through cmd.exe on Windows runners and sh
+// elsewhere.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,18 @@
+package hooks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{log: func(args ...interface{}) { lines = append(lines, args[0].(string)) }}
+	w.Write([]byte("first\r\nsec"))
+	w.Write([]byte("ond\nthird"))
+	w.Flush()
+
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
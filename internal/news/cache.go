@@ -0,0 +1,103 @@
+package news
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ArticleCache keeps the article bodies fetched on earlier runs, keyed by
+// the listing entry that linked to them. TeamUnify sends no validators for
+// article pages, so an unchanged listing entry is the cheapest sign the
+// article is unchanged too.
+type ArticleCache struct {
+	Path string
+	// MaxAge bounds how long a body is trusted, so edits the listing
+	// doesn't show are still picked up.
+	MaxAge time.Duration
+
+	entries map[string]cachedArticle
+}
+
+type cachedArticle struct {
+	ID      string    `json:"id"`
+	Date    string    `json:"date"`
+	Title   string    `json:"title"`
+	Summary string    `json:"summary"`
+	Fetched time.Time `json:"fetched"`
+	Article Article   `json:"article"`
+}
+
+// Load reads the cache file; a missing file is an empty cache.
+func (c *ArticleCache) Load() error {
+	c.entries = map[string]cachedArticle{}
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("article cache read failed: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return fmt.Errorf("article cache parse failed: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the cached article for an entry whose ID, date, title
+// and summary are all as they were when it was fetched. Entries without an
+// ID or a date are never trusted.
+func (c *ArticleCache) Lookup(entry ListingEntry, now time.Time) (Article, bool) {
+	if entry.ID == "" || entry.Date == "" {
+		return Article{}, false
+	}
+	cached, ok := c.entries[entry.URL]
+	if !ok || now.Sub(cached.Fetched) > c.MaxAge {
+		return Article{}, false
+	}
+	if cached.ID != entry.ID || cached.Date != entry.Date || cached.Title != entry.Title || cached.Summary != entry.Summary {
+		return Article{}, false
+	}
+	return cached.Article, true
+}
+
+// Save writes the cache with only the given entries, so articles that
+// left the listing are dropped. fetched holds this run's new bodies.
+func (c *ArticleCache) Save(entries []ListingEntry, fetched map[string]Article, now time.Time) error {
+	kept := make(map[string]cachedArticle, len(entries))
+	for _, entry := range entries {
+		if article, ok := fetched[entry.URL]; ok {
+			kept[entry.URL] = cachedArticle{
+				ID:      entry.ID,
+				Date:    entry.Date,
+				Title:   entry.Title,
+				Summary: entry.Summary,
+				Fetched: now,
+				Article: article,
+			}
+		} else if cached, ok := c.entries[entry.URL]; ok {
+			kept[entry.URL] = cached
+		}
+	}
+	c.entries = kept
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return fmt.Errorf("article cache encode failed: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return fmt.Errorf("article cache dir create failed: %w", err)
+	}
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("article cache write failed: %w", err)
+	}
+	if err := os.Rename(tmp, c.Path); err != nil {
+		return fmt.Errorf("article cache write failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package news
+
+import (
+	"sync"
+)
+
+// Failures collects the article URLs that failed to fetch during a run.
+// It is safe for concurrent use; a nil *Failures discards everything.
+type Failures struct {
+	mu   sync.Mutex
+	errs map[string]string
+}
+
+// Add records that url failed with err.
+func (f *Failures) Add(url string, err error) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.errs == nil {
+		f.errs = map[string]string{}
+	}
+	f.errs[url] = err.Error()
+}
+
+// Errors returns a copy of the failed URLs and their errors.
+func (f *Failures) Errors() map[string]string {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	errs := make(map[string]string, len(f.errs))
+	for url, err := range f.errs {
+		errs[url] = err
+	}
+	return errs
+}
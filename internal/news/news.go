@@ -0,0 +1,534 @@
+package news
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	TimeFormat  = "January 2, 2006"
+	UnknownDate = "Unknown Date"
+
+	listingItems = "div.Item:not(.Supplement) a[href]"
+)
+
+type Article struct {
+	Title   string
+	Date    string
+	Author  string
+	Content string
+	URL     string
+	// Markdown is Content converted for text-only outputs.
+	Markdown string
+
+	// Published is the parsed publication time; zero when Date is UnknownDate.
+	Published time.Time
+	// ListingIndex is the article's position on the listing page.
+	ListingIndex int
+	// Updated is when a sync noticed the article being edited after it was
+	// published; zero when it never was.
+	Updated time.Time
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"01/02/2006",
+	"1/2/2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"Mon, Jan 2, 2006",
+}
+
+var nextLinkText = map[string]bool{
+	"next":           true,
+	"next »":         true,
+	"next page":      true,
+	"older":          true,
+	"older news":     true,
+	"older articles": true,
+	"load more":      true,
+	"more news":      true,
+	"»":              true,
+}
+
+var articleID = regexp.MustCompile(`\d+$`)
+
+var visibleDate = regexp.MustCompile(`(?i)\b(?:\d{1,2}/\d{1,2}/\d{4}|(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.? \d{1,2}, \d{4})\b`)
+
+// Scraper pulls articles from a TeamUnify news listing.
+type Scraper struct {
+	ListingURL  string
+	BaseURL     string
+	Concurrency int
+	// MaxPages bounds how many listing pages are followed; zero means one.
+	MaxPages int
+	// RequestTimeout caps each page fetch; zero leaves it to the context.
+	RequestTimeout time.Duration
+	Client         *http.Client
+	Pipeline       *content.Pipeline
+	// Cache skips fetching articles whose listing entry is unchanged; nil
+	// fetches every article.
+	Cache *ArticleCache
+	// Failures, when set, receives the articles that failed to fetch, and
+	// Priority lists URLs fetched ahead of the rest, such as the ones that
+	// failed last run.
+	Failures *Failures
+	Priority map[string]bool
+	Log      *logrus.Logger
+}
+
+// Run fetches the listing and every article on it, newest first.
+func (s *Scraper) Run(ctx context.Context) ([]Article, error) {
+	entries, err := s.Listing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.Fetch(ctx, entries)
+}
+
+// Fetch returns the articles behind the listing entries, newest first,
+// from the cache where the entry is unchanged.
+func (s *Scraper) Fetch(ctx context.Context, entries []ListingEntry) ([]Article, error) {
+	if s.Cache == nil {
+		return s.Articles(ctx, entryURLs(entries))
+	}
+
+	if err := s.Cache.Load(); err != nil {
+		s.Log.Warnf("ignoring article cache: %v", err)
+	}
+	now := time.Now()
+	var articles []Article
+	var stale []ListingEntry
+	var staleIndex []int
+	for i, entry := range entries {
+		if article, ok := s.Cache.Lookup(entry, now); ok {
+			article.ListingIndex = i
+			articles = append(articles, article)
+			continue
+		}
+		stale = append(stale, entry)
+		staleIndex = append(staleIndex, i)
+	}
+	s.Log.Infof("%d articles unchanged on the listing, fetching %d", len(articles), len(stale))
+
+	fetched, err := s.Articles(ctx, entryURLs(stale))
+	if err != nil {
+		return nil, err
+	}
+	bodies := make(map[string]Article, len(fetched))
+	for _, article := range fetched {
+		bodies[article.URL] = article
+		article.ListingIndex = staleIndex[article.ListingIndex]
+		articles = append(articles, article)
+	}
+	if err := s.Cache.Save(entries, bodies, now); err != nil {
+		s.Log.Warnf("failed to save article cache: %v", err)
+	}
+
+	SortByDate(articles)
+	return articles, nil
+}
+
+// ListingEntry is an article link found on the listing pages, with what
+// the listing shows about it.
+type ListingEntry struct {
+	URL   string
+	Title string
+	// ID is TeamUnify's article id, from the item or the link.
+	ID string
+	// Date is the listing's date for the article as written, if any.
+	Date string
+	// Summary is the rest of the item's text, usually the lede.
+	Summary string
+}
+
+func entryURLs(entries []ListingEntry) []string {
+	urls := make([]string, len(entries))
+	for i, entry := range entries {
+		urls[i] = entry.URL
+	}
+	return urls
+}
+
+func (s *Scraper) ArticleURLs(ctx context.Context) ([]string, error) {
+	entries, err := s.Listing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entryURLs(entries), nil
+}
+
+// Listing follows up to MaxPages listing pages and returns each article
+// link with its link text, which is the article title on TeamUnify, and
+// the id, date and summary shown around it.
+func (s *Scraper) Listing(ctx context.Context) ([]ListingEntry, error) {
+	maxPages := s.MaxPages
+	if maxPages < 1 {
+		maxPages = 1
+	}
+
+	var entries []ListingEntry
+	seen := map[string]bool{}
+	visited := map[string]bool{}
+	pageURL := s.ListingURL
+
+	for page := 1; page <= maxPages && pageURL != "" && !visited[pageURL]; page++ {
+		s.Log.Infof("fetching news listing page %d", page)
+		visited[pageURL] = true
+
+		doc, err := s.fetchDocument(ctx, pageURL, listingItems)
+		var blocked *fetch.BlockedError
+		if err != nil {
+			if page == 1 || errors.As(err, &blocked) || ctx.Err() != nil {
+				return nil, err
+			}
+			s.Log.Warnf("stopping pagination at page %d: %v", page, err)
+			break
+		}
+
+		doc.Find(listingItems).Each(func(i int, sel *goquery.Selection) {
+			if href, exists := sel.Attr("href"); exists && !seen[s.BaseURL+href] {
+				seen[s.BaseURL+href] = true
+				entries = append(entries, listingEntry(sel, s.BaseURL+href))
+			}
+		})
+
+		pageURL = s.nextPageURL(doc, pageURL)
+	}
+
+	s.Log.Infof("found %d articles", len(entries))
+	return entries, nil
+}
+
+// listingEntry describes the article link sel from its surrounding
+// listing item.
+func listingEntry(sel *goquery.Selection, articleURL string) ListingEntry {
+	entry := ListingEntry{URL: articleURL, Title: content.Clean(sel.Text())}
+	item := sel.Closest("div.Item")
+
+	entry.ID = item.AttrOr("data-id", "")
+	if entry.ID == "" {
+		entry.ID = articleID.FindString(strings.SplitN(articleURL, "?", 2)[0])
+	}
+
+	dateStr := item.Find("span.DateStr")
+	entry.Date = strings.TrimSpace(dateStr.AttrOr("data", ""))
+	if entry.Date == "" {
+		entry.Date = content.Clean(dateStr.Text())
+	}
+	if entry.Date == "" {
+		entry.Date = visibleDate.FindString(item.Text())
+	}
+
+	rest := item.Clone()
+	rest.Find("a[href]").FilterFunction(func(i int, a *goquery.Selection) bool {
+		return content.Clean(a.Text()) == entry.Title
+	}).Remove()
+	rest.Find("span.DateStr").Remove()
+	entry.Summary = content.Clean(rest.Text())
+	return entry
+}
+
+// nextPageURL looks for a rel=next link, a pagination "Next" control or a
+// "load more" style link on the listing page.
+func (s *Scraper) nextPageURL(doc *goquery.Document, current string) string {
+	href, _ := doc.Find(`a[rel="next"]`).First().Attr("href")
+	if href == "" {
+		href, _ = doc.Find(".Pagination a.Next, .pagination a.next").First().Attr("href")
+	}
+	if href == "" {
+		doc.Find("a[href]").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+			if nextLinkText[strings.ToLower(strings.TrimSpace(sel.Text()))] {
+				href, _ = sel.Attr("href")
+				return false
+			}
+			return true
+		})
+	}
+
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+		return ""
+	}
+
+	base, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// Articles fetches the given article pages concurrently. Individual
+// failures are logged and skipped, but an interstitial or an open circuit
+// breaker aborts the whole batch since the rest of the site is behind it
+// too: nothing more is requested and that error is returned.
+func (s *Scraper) Articles(ctx context.Context, urls []string) ([]Article, error) {
+	batch, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var blockedOnce sync.Once
+	var blockedErr error
+	ch := make(chan int, s.Concurrency)
+	results := make(chan Article, len(urls))
+
+	for i := 0; i < s.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				if batch.Err() != nil {
+					continue
+				}
+				article, err := s.fetchArticle(batch, urls[idx])
+				var blocked *fetch.BlockedError
+				var unavailable *fetch.UnavailableError
+				if errors.As(err, &blocked) || errors.As(err, &unavailable) {
+					blockedOnce.Do(func() {
+						blockedErr = err
+						cancel()
+					})
+					continue
+				}
+				if err != nil && batch.Err() != nil {
+					continue
+				}
+				if err != nil {
+					s.Log.Warnf("failed to process %s: %v", urls[idx], err)
+					s.Failures.Add(urls[idx], err)
+					continue
+				}
+				article.ListingIndex = idx
+				results <- article
+			}
+		}()
+	}
+
+	order := make([]int, 0, len(urls))
+	for idx, u := range urls {
+		if s.Priority[u] {
+			order = append(order, idx)
+		}
+	}
+	for idx, u := range urls {
+		if !s.Priority[u] {
+			order = append(order, idx)
+		}
+	}
+
+dispatch:
+	for _, idx := range order {
+		select {
+		case ch <- idx:
+		case <-batch.Done():
+			break dispatch
+		}
+	}
+	close(ch)
+	wg.Wait()
+	close(results)
+
+	var articles []Article
+	for article := range results {
+		articles = append(articles, article)
+	}
+
+	if blockedErr != nil {
+		return nil, blockedErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("article fetch aborted: %w", err)
+	}
+
+	SortByDate(articles)
+	return articles, nil
+}
+
+// fetchDocument fetches and parses a page. When the page doesn't contain
+// the expected selector it is checked for login/CAPTCHA/maintenance
+// interstitials, which are returned as *fetch.BlockedError.
+func (s *Scraper) fetchDocument(ctx context.Context, pageURL, expect string) (*goquery.Document, error) {
+	if s.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request creation failed: %w", err)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("html parsing failed: %w", err)
+	}
+
+	if doc.Find(expect).Length() == 0 {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+	}
+	return doc, nil
+}
+
+func (s *Scraper) fetchArticle(ctx context.Context, articleURL string) (Article, error) {
+	doc, err := s.fetchDocument(ctx, articleURL, "div.NewsItem")
+	if err != nil {
+		return Article{}, err
+	}
+
+	newsItem := doc.Find("div.NewsItem")
+	if newsItem.Length() == 0 {
+		return Article{}, fmt.Errorf("news item not found")
+	}
+
+	title := newsItem.Find("h1").Text()
+	author := newsItem.Find("div.Author strong").Text()
+	html, _ := newsItem.Find("div.Content").Html()
+
+	body, err := s.Pipeline.Process(html)
+	if err != nil {
+		s.Log.Warnf("content processing failed for %s: %v", articleURL, err)
+	}
+
+	markdown, err := content.Markdown(body)
+	if err != nil {
+		s.Log.Warnf("markdown conversion failed for %s: %v", articleURL, err)
+	}
+
+	article := Article{
+		Title:    content.Clean(title),
+		Date:     UnknownDate,
+		Author:   content.Clean(author),
+		Content:  body,
+		URL:      articleURL,
+		Markdown: markdown,
+	}
+
+	if published, ok := articleDate(newsItem); ok {
+		article.Published = published
+		article.Date = published.Format(TimeFormat)
+	} else {
+		s.Log.Warnf("unable to determine date for %s", articleURL)
+	}
+	return article, nil
+}
+
+// parseDate accepts the formats TeamUnify has been seen to use: epoch
+// milliseconds, RFC3339, US numeric dates and spelled-out month names.
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if unixMillis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(unixMillis), true
+	}
+
+	normalized := strings.NewReplacer(".", "", "Sept ", "Sep ").Replace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// articleDate tries each date source on the article page in turn, ending
+// with any date-like text in the header around the title.
+func articleDate(newsItem *goquery.Selection) (time.Time, bool) {
+	dateStr := newsItem.Find("span.DateStr")
+	data, _ := dateStr.Attr("data")
+
+	header := newsItem.Clone()
+	header.Find("div.Content").Remove()
+
+	candidates := []string{data, dateStr.Text()}
+	candidates = append(candidates, visibleDate.FindAllString(header.Text(), -1)...)
+
+	for _, candidate := range candidates {
+		if t, ok := parseDate(candidate); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SortByDate orders articles newest first. An undated article borrows the
+// date of the article listed just above it on TeamUnify (or the first dated
+// one when it tops the listing), so it keeps its listing position instead
+// of sinking to the bottom.
+func SortByDate(articles []Article) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].ListingIndex < articles[j].ListingIndex
+	})
+
+	effective := make(map[int]time.Time, len(articles))
+	var previous time.Time
+	firstDated := -1
+	for i, article := range articles {
+		if !article.Published.IsZero() {
+			previous = article.Published
+			if firstDated < 0 {
+				firstDated = i
+			}
+		}
+		effective[article.ListingIndex] = previous
+	}
+	for i := 0; i < firstDated; i++ {
+		effective[articles[i].ListingIndex] = articles[firstDated].Published
+	}
+
+	sort.SliceStable(articles, func(i, j int) bool {
+		ti, tj := effective[articles[i].ListingIndex], effective[articles[j].ListingIndex]
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return articles[i].ListingIndex < articles[j].ListingIndex
+	})
+}
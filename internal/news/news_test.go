@@ -0,0 +1,394 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestScraper(t *testing.T) *Scraper {
+	t.Helper()
+
+	pipeline, err := content.NewPipeline(nil, content.Options{BaseURL: "https://www.gomotionapp.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return &Scraper{
+		ListingURL:  "https://www.gomotionapp.com/team/cadas/page/news",
+		BaseURL:     "https://www.gomotionapp.com",
+		Concurrency: 2,
+		Client: &http.Client{
+			Transport: &fetch.Recorder{Mode: fetch.ModeReplay, Dir: "testdata/teamunify"},
+		},
+		Pipeline: pipeline,
+		Log:      log,
+	}
+}
+
+func TestScraperReplay(t *testing.T) {
+	articles, err := newTestScraper(t).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(articles) != 2 {
+		t.Fatalf("got %d articles, want 2", len(articles))
+	}
+
+	first, second := articles[0], articles[1]
+	if first.Title != "Pool Closure Notice" || first.Date != "April 15, 2024" || first.Author != "DARE Office" {
+		t.Errorf("unexpected first article: %+v", first)
+	}
+	if second.Title != "Spring Invitational Results" || second.Date != "April 1, 2024" || second.Author != "Coach Dana" {
+		t.Errorf("unexpected second article: %+v", second)
+	}
+
+	if strings.Contains(first.Content, "<script") {
+		t.Errorf("script survived sanitizing: %s", first.Content)
+	}
+	if !strings.Contains(first.Content, "<li>Monday closed</li><li>Tuesday open</li>") {
+		t.Errorf("list items not joined: %s", first.Content)
+	}
+	if !strings.Contains(second.Content, `href="https://www.gomotionapp.com/team/cadas/page/results"`) {
+		t.Errorf("relative link not rewritten: %s", second.Content)
+	}
+}
+
+func TestScraperReplayMissingFixture(t *testing.T) {
+	s := newTestScraper(t)
+	s.ListingURL = "https://www.gomotionapp.com/team/other/page/news"
+
+	if _, err := s.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Fatalf("expected missing fixture error, got %v", err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"2024-04-15T09:00:00-07:00", "2024-04-15", true},
+		{"2024-04-15T09:00:00.000Z", "2024-04-15", true},
+		{"1711972800000", "2024-04-01", true},
+		{"04/15/2024", "2024-04-15", true},
+		{"4/5/2024", "2024-04-05", true},
+		{"April 15, 2024", "2024-04-15", true},
+		{"Apr. 15, 2024", "2024-04-15", true},
+		{"Sept 3, 2024", "2024-09-03", true},
+		{"  ", "", false},
+		{"next Tuesday", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseDate(tt.in)
+		if ok != tt.ok {
+			t.Errorf("parseDate(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			continue
+		}
+		if ok && got.UTC().Format("2006-01-02") != tt.want {
+			t.Errorf("parseDate(%q) = %s, want %s", tt.in, got.UTC().Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestArticleDateFallsBackToHeaderText(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="NewsItem">
+		<h1>Meet Reminder</h1>
+		<span class="DateStr" data=""></span>
+		<div class="Author">Posted 03/05/2024 by <strong>Coach Dana</strong></div>
+		<div class="Content"><p>Warmups at 7:00, see you 12/31/2099.</p></div>
+	</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := articleDate(doc.Find("div.NewsItem"))
+	if !ok || got.Format("2006-01-02") != "2024-03-05" {
+		t.Errorf("articleDate = %s, %v; want 2024-03-05", got, ok)
+	}
+}
+
+func TestSortByDateKeepsUndatedInListingPosition(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 12, 0, 0, 0, time.UTC) }
+	articles := []Article{
+		{Title: "old", Published: day(1), ListingIndex: 4},
+		{Title: "undated-top", ListingIndex: 0},
+		{Title: "undated-middle", ListingIndex: 3},
+		{Title: "newest", Published: day(20), ListingIndex: 1},
+		{Title: "newer", Published: day(10), ListingIndex: 2},
+		{Title: "undated-bottom", ListingIndex: 5},
+	}
+
+	SortByDate(articles)
+
+	want := []string{"undated-top", "newest", "newer", "undated-middle", "old", "undated-bottom"}
+	for i, article := range articles {
+		if article.Title != want[i] {
+			t.Fatalf("position %d = %s, want %s (full order %v)", i, article.Title, want[i], articles)
+		}
+	}
+}
+
+func TestScraperFollowsPagination(t *testing.T) {
+	s := newTestScraper(t)
+	s.MaxPages = 3
+
+	articles, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	for _, article := range articles {
+		titles = append(titles, article.Title)
+	}
+	want := "Pool Closure Notice,Spring Invitational Results,Season Kickoff"
+	if got := strings.Join(titles, ","); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestScraperDetectsCaptchaInterstitial(t *testing.T) {
+	s := newTestScraper(t)
+	s.ListingURL = "https://www.gomotionapp.com/team/walled/page/news"
+
+	_, err := s.Run(context.Background())
+	var blocked *fetch.BlockedError
+	if !errors.As(err, &blocked) || blocked.Reason != fetch.BlockedCaptcha {
+		t.Fatalf("expected captcha BlockedError, got %v", err)
+	}
+}
+
+func TestScraperRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/listing" {
+			io.WriteString(w, `<div class="Item"><a href="/hung">Hung</a></div><div class="Item"><a href="/ok">OK</a></div>`)
+			return
+		}
+		if r.URL.Path == "/hung" {
+			<-release
+		}
+		io.WriteString(w, `<div class="NewsItem"><h1>OK</h1><div class="Content"></div></div>`)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	s := newTestScraper(t)
+	s.ListingURL = srv.URL + "/listing"
+	s.BaseURL = srv.URL
+	s.Client = srv.Client()
+	s.RequestTimeout = 50 * time.Millisecond
+
+	articles, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].Title != "OK" {
+		t.Errorf("expected only the responsive article, got %+v", articles)
+	}
+}
+
+func TestListingEntryMetadata(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="Item" data-id="77">
+		<a href="/news/article/1001">Spring Invitational</a>
+		<span class="DateStr" data="1714521600000">May 1, 2024</span>
+		<p>Results from the weekend.</p>
+	</div>
+	<div class="Item"><a href="/news/article/1002?ref=list">Pool Closure</a> posted May 3, 2024</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []ListingEntry
+	doc.Find(listingItems).Each(func(i int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		entries = append(entries, listingEntry(sel, "https://example.com"+href))
+	})
+
+	want := []ListingEntry{
+		{URL: "https://example.com/news/article/1001", Title: "Spring Invitational", ID: "77", Date: "1714521600000", Summary: "Results from the weekend."},
+		{URL: "https://example.com/news/article/1002?ref=list", Title: "Pool Closure", ID: "1002", Date: "May 3, 2024", Summary: "posted May 3, 2024"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %+v", entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestScraperArticleCache(t *testing.T) {
+	listing := `<div class="Item"><a href="/a/1">One</a> <span class="DateStr">May 1, 2024</span></div>` +
+		`<div class="Item"><a href="/a/2">Two</a> <span class="DateStr">May 2, 2024</span></div>`
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/listing" {
+			io.WriteString(w, listing)
+			return
+		}
+		fetched = append(fetched, r.URL.Path)
+		io.WriteString(w, `<div class="NewsItem"><h1>Article `+r.URL.Path+`</h1><span class="DateStr">May 1, 2024</span><div class="Content"><p>Body</p></div></div>`)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.ListingURL = srv.URL + "/listing"
+	s.BaseURL = srv.URL
+	s.Client = srv.Client()
+	s.Concurrency = 1
+	s.Cache = &ArticleCache{Path: t.TempDir() + "/articles.json", MaxAge: time.Hour}
+
+	run := func() []Article {
+		t.Helper()
+		fetched = nil
+		articles, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(articles) != 2 {
+			t.Fatalf("expected 2 articles, got %+v", articles)
+		}
+		return articles
+	}
+
+	run()
+	if len(fetched) != 2 {
+		t.Fatalf("first run fetched %v", fetched)
+	}
+
+	articles := run()
+	if len(fetched) != 0 {
+		t.Errorf("unchanged listing fetched %v", fetched)
+	}
+	if articles[0].Title != "Article /a/1" || articles[0].Content == "" {
+		t.Errorf("cached article lost its content: %+v", articles[0])
+	}
+
+	listing = strings.Replace(listing, "May 2, 2024", "May 9, 2024", 1)
+	run()
+	if len(fetched) != 1 || fetched[0] != "/a/2" {
+		t.Errorf("changed entry fetched %v, want only /a/2", fetched)
+	}
+}
+
+func TestArticleCacheNeedsIDAndDate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []ListingEntry{
+		{URL: "/a/1", ID: "1", Date: "May 1, 2024", Title: "Dated"},
+		{URL: "/a/2", ID: "2", Title: "Undated"},
+		{URL: "/a/3", Date: "May 1, 2024", Title: "No ID"},
+	}
+	fetched := map[string]Article{}
+	for _, entry := range entries {
+		fetched[entry.URL] = Article{Title: entry.Title}
+	}
+
+	c := &ArticleCache{Path: t.TempDir() + "/articles.json", MaxAge: time.Hour}
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(entries, fetched, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Lookup(entries[0], now); !ok {
+		t.Error("dated entry with an ID missed the cache")
+	}
+	if article, ok := c.Lookup(entries[1], now); ok {
+		t.Errorf("undated entry reused %+v", article)
+	}
+	if article, ok := c.Lookup(entries[2], now); ok {
+		t.Errorf("entry without an ID reused %+v", article)
+	}
+}
+
+func TestScraperFailuresAndPriority(t *testing.T) {
+	var order []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, r.URL.Path)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, `<div class="NewsItem"><h1>OK</h1><div class="Content"></div></div>`)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.Client = srv.Client()
+	s.Concurrency = 1
+	s.Failures = &Failures{}
+	s.Priority = map[string]bool{srv.URL + "/retry": true}
+
+	articles, err := s.Articles(context.Background(), []string{srv.URL + "/first", srv.URL + "/broken", srv.URL + "/retry"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 || order[0] != "/retry" {
+		t.Errorf("got %d articles fetched in order %v", len(articles), order)
+	}
+	if errs := s.Failures.Errors(); len(errs) != 1 || !strings.Contains(errs[srv.URL+"/broken"], "500") {
+		t.Errorf("unexpected failures %v", errs)
+	}
+}
+
+func TestScraperStopsAfterBlockedArticle(t *testing.T) {
+	var mu sync.Mutex
+	var blocked bool
+	var after []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if blocked && r.URL.Path != "/down" {
+			after = append(after, r.URL.Path)
+		}
+		if r.URL.Path == "/down" {
+			blocked = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `<div class="NewsItem"><h1>OK</h1><div class="Content"></div></div>`)
+	}))
+	defer srv.Close()
+
+	s := newTestScraper(t)
+	s.Client = srv.Client()
+	s.Concurrency = 1
+	s.Failures = &Failures{}
+
+	urls := []string{srv.URL + "/first", srv.URL + "/down"}
+	for _, path := range []string{"/a", "/b", "/c", "/d"} {
+		urls = append(urls, srv.URL+path)
+	}
+	_, err := s.Articles(context.Background(), urls)
+	var blockedErr *fetch.BlockedError
+	if !errors.As(err, &blockedErr) {
+		t.Fatalf("expected a BlockedError, got %v", err)
+	}
+	if len(after) > 0 {
+		t.Errorf("requested %v after the blocked response", after)
+	}
+	if errs := s.Failures.Errors(); len(errs) > 0 {
+		t.Errorf("unexpected failures %v", errs)
+	}
+}
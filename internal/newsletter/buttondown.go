@@ -0,0 +1,33 @@
+package newsletter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const buttondownURL = "https://api.buttondown.email/v1/emails"
+
+// Buttondown creates drafts through the Buttondown emails API. Bodies are
+// sent as Markdown, which Buttondown renders itself.
+type Buttondown struct {
+	apiKey string
+	http   *http.Client
+}
+
+func (b *Buttondown) CreateDraft(ctx context.Context, draft Draft) (string, error) {
+	body := map[string]string{
+		"subject": draft.Subject,
+		"body":    draft.Markdown,
+		"status":  "draft",
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	auth := func(req *http.Request) { req.Header.Set("Authorization", "Token "+b.apiKey) }
+	if err := doJSON(ctx, b.http, "POST", buttondownURL, auth, body, &created); err != nil {
+		return "", fmt.Errorf("draft create failed: %w", err)
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,64 @@
+package newsletter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Mailchimp creates a regular campaign for a list and fills in its HTML
+// content, leaving it unsent.
+type Mailchimp struct {
+	baseURL string
+	apiKey  string
+	opts    Options
+	http    *http.Client
+}
+
+// newMailchimp derives the API host from the data center suffix of the key
+// ("...-us21").
+func newMailchimp(opts Options, httpClient *http.Client) (*Mailchimp, error) {
+	_, dc, ok := strings.Cut(opts.APIKey, "-")
+	if !ok || dc == "" {
+		return nil, fmt.Errorf("mailchimp api key must end in -<datacenter>")
+	}
+	if opts.ListID == "" {
+		return nil, fmt.Errorf("mailchimp requires newsletter.list_id")
+	}
+
+	return &Mailchimp{
+		baseURL: "https://" + dc + ".api.mailchimp.com/3.0",
+		apiKey:  opts.APIKey,
+		opts:    opts,
+		http:    httpClient,
+	}, nil
+}
+
+func (m *Mailchimp) CreateDraft(ctx context.Context, draft Draft) (string, error) {
+	auth := func(req *http.Request) { req.SetBasicAuth("synchandler", m.apiKey) }
+
+	campaign := map[string]interface{}{
+		"type":       "regular",
+		"recipients": map[string]string{"list_id": m.opts.ListID},
+		"settings": map[string]string{
+			"subject_line": draft.Subject,
+			"title":        draft.Subject,
+			"from_name":    m.opts.FromName,
+			"reply_to":     m.opts.ReplyTo,
+		},
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := doJSON(ctx, m.http, "POST", m.baseURL+"/campaigns", auth, campaign, &created); err != nil {
+		return "", fmt.Errorf("campaign create failed: %w", err)
+	}
+
+	content := map[string]string{"html": draft.HTML}
+	if err := doJSON(ctx, m.http, "PUT", m.baseURL+"/campaigns/"+created.ID+"/content", auth, content, nil); err != nil {
+		return "", fmt.Errorf("campaign content failed: %w", err)
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,85 @@
+package newsletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Draft is a campaign left unsent for a volunteer to review.
+type Draft struct {
+	Subject  string
+	HTML     string
+	Markdown string
+}
+
+// Client creates draft campaigns on a newsletter platform and returns a
+// link or ID for the new draft.
+type Client interface {
+	CreateDraft(ctx context.Context, draft Draft) (string, error)
+}
+
+// Options carries the provider settings from config.
+type Options struct {
+	APIKey   string
+	ListID   string
+	FromName string
+	ReplyTo  string
+}
+
+// New returns the client for provider ("buttondown" or "mailchimp").
+func New(provider string, opts Options, httpClient *http.Client) (Client, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("missing newsletter api key")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	switch provider {
+	case "buttondown":
+		return &Buttondown{apiKey: opts.APIKey, http: httpClient}, nil
+	case "mailchimp":
+		return newMailchimp(opts, httpClient)
+	default:
+		return nil, fmt.Errorf("unknown newsletter provider %q", provider)
+	}
+}
+
+// doJSON sends body as JSON and decodes a JSON response into out.
+func doJSON(ctx context.Context, client *http.Client, method, url string, auth func(*http.Request), body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("json encode failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	auth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("json decode failed: %w", err)
+	}
+	return nil
+}
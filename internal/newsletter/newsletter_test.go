@@ -0,0 +1,52 @@
+package newsletter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMailchimpCreateDraft(t *testing.T) {
+	var calls []string
+	var content map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if _, key, _ := r.BasicAuth(); key != "abc-us21" {
+			t.Errorf("api key = %q", key)
+		}
+		switch r.URL.Path {
+		case "/campaigns":
+			w.Write([]byte(`{"id":"c1"}`))
+		case "/campaigns/c1/content":
+			json.NewDecoder(r.Body).Decode(&content)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New("mailchimp", Options{APIKey: "abc-us21", ListID: "list"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc := client.(*Mailchimp)
+	if mc.baseURL != "https://us21.api.mailchimp.com/3.0" {
+		t.Errorf("baseURL = %q", mc.baseURL)
+	}
+	mc.baseURL = server.URL
+
+	id, err := mc.CreateDraft(context.Background(), Draft{Subject: "News", HTML: "<p>Hi</p>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "c1" || len(calls) != 2 || calls[1] != "PUT /campaigns/c1/content" || content["html"] != "<p>Hi</p>" {
+		t.Errorf("id = %q, calls = %v, content = %v", id, calls, content)
+	}
+}
+
+func TestNewRequiresKey(t *testing.T) {
+	if _, err := New("buttondown", Options{}, nil); err == nil {
+		t.Error("New without an api key succeeded")
+	}
+}
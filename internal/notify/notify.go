@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dareaquatics/dare-website/internal/secrets"
+)
+
+const (
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+	FormatJSON    = "json"
+)
+
+// Notifier posts run alerts to a chat or generic webhook. The zero value
+// (no URL) silently drops messages.
+type Notifier struct {
+	WebhookURL string
+	Format     string
+	Handler    string
+	Client     *http.Client
+}
+
+// New builds a notifier, letting SYNC_NOTIFY_WEBHOOK override the
+// configured URL so the webhook can live in a secret.
+func New(webhookURL, format, handler string, client *http.Client) *Notifier {
+	if env := secrets.Get("SYNC_NOTIFY_WEBHOOK"); env != "" {
+		webhookURL = env
+	}
+	if format == "" {
+		format = FormatSlack
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Notifier{WebhookURL: webhookURL, Format: format, Handler: handler, Client: client}
+}
+
+func (n *Notifier) Send(level, message string) error {
+	if n == nil || n.WebhookURL == "" {
+		return nil
+	}
+
+	message = secrets.Redact(message)
+	text := fmt.Sprintf("[%s sync] %s: %s", n.Handler, level, message)
+	var payload interface{}
+	switch n.Format {
+	case FormatDiscord:
+		payload = map[string]string{"content": text}
+	case FormatJSON:
+		payload = map[string]string{"handler": n.Handler, "level": level, "message": message}
+	default:
+		payload = map[string]string{"text": text}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("notification encode failed: %w", err)
+	}
+
+	resp, err := n.Client.Post(n.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notification failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification failed: unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
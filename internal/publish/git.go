@@ -0,0 +1,177 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/sirupsen/logrus"
+)
+
+// Git commits changed files in the repository at Dir and pushes them to
+// one or more remotes.
+type Git struct {
+	Dir   string
+	Token string
+	// Branch, when set, receives the commit instead of the checked-out
+	// branch. It is recreated from HEAD and force-pushed each time.
+	Branch string
+	// Author and Committer sign the commits. An empty Author is
+	// github-actions[bot]; an empty Committer is the author.
+	Author    Identity
+	Committer Identity
+	Log       *logrus.Logger
+}
+
+// Identity is a commit author or committer.
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// Trailer formats the identity for a Co-authored-by line.
+func (id Identity) Trailer() string {
+	return "Co-authored-by: " + id.Name + " <" + id.Email + ">"
+}
+
+// Remote is where Push sends the commit.
+type Remote struct {
+	// Name is a configured remote or a URL; empty means origin.
+	Name string
+	// Branch is the remote branch to update, defaulting to the local one.
+	Branch string
+	// Token replaces Git.Token for this remote.
+	Token string
+}
+
+// Commit adds files and commits them locally, on Branch when it is set.
+func (g *Git) Commit(files []string, message string) error {
+	g.Log.Info("committing changes to git")
+	repo, err := git.PlainOpen(g.Dir)
+	if err != nil {
+		return fmt.Errorf("repo open failed: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("worktree access failed: %w", err)
+	}
+
+	if g.Branch != "" {
+		if err := g.switchBranch(repo, wt); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if _, err := wt.Add(filepath.ToSlash(file)); err != nil {
+			return fmt.Errorf("git add failed: %w", err)
+		}
+	}
+
+	author, committer := g.signatures()
+	if _, err := wt.Commit(message, &git.CommitOptions{Author: author, Committer: committer}); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// Push sends the last commit to remote.
+func (g *Git) Push(ctx context.Context, remote Remote) error {
+	repo, err := git.PlainOpen(g.Dir)
+	if err != nil {
+		return fmt.Errorf("repo open failed: %w", err)
+	}
+	return g.pushTo(ctx, repo, remote)
+}
+
+var botIdentity = Identity{Name: "github-actions[bot]", Email: "github-actions[bot]@users.noreply.github.com"}
+
+// signatures returns the author and committer for a commit made now.
+func (g *Git) signatures() (author, committer *object.Signature) {
+	now := time.Now()
+	a := g.Author
+	if a.Name == "" {
+		a = botIdentity
+	}
+	c := g.Committer
+	if c.Name == "" {
+		c = a
+	}
+	return &object.Signature{Name: a.Name, Email: a.Email, When: now},
+		&object.Signature{Name: c.Name, Email: c.Email, When: now}
+}
+
+func (g *Git) push(ctx context.Context, repo *git.Repository) error {
+	return g.pushTo(ctx, repo, Remote{})
+}
+
+func (g *Git) pushTo(ctx context.Context, repo *git.Repository, remote Remote) error {
+	token := remote.Token
+	if token == "" {
+		token = g.Token
+	}
+	auth := &gitHttp.BasicAuth{
+		Username: "github-actions",
+		Password: token,
+	}
+
+	opts := &git.PushOptions{Auth: auth, RemoteName: remote.Name}
+	if strings.Contains(remote.Name, "://") {
+		opts.RemoteName, opts.RemoteURL = "", remote.Name
+	}
+
+	if g.Branch != "" || remote.Branch != "" {
+		src := plumbing.NewBranchReferenceName(g.Branch)
+		if g.Branch == "" {
+			head, err := repo.Head()
+			if err != nil {
+				return fmt.Errorf("head lookup failed: %w", err)
+			}
+			src = head.Name()
+		}
+		dst := src
+		if remote.Branch != "" {
+			dst = plumbing.NewBranchReferenceName(remote.Branch)
+		}
+
+		// The freeze branch is recreated each run, so it needs a force push
+		spec := src.String() + ":" + dst.String()
+		if g.Branch != "" {
+			spec = "+" + spec
+		}
+		opts.RefSpecs = []gitConfig.RefSpec{gitConfig.RefSpec(spec)}
+	}
+
+	if err := repo.PushContext(ctx, opts); err != nil {
+		return fmt.Errorf("push failed: %w", err)
+	}
+
+	g.Log.Info("changes pushed successfully")
+	return nil
+}
+
+// switchBranch points Branch at HEAD and checks it out, keeping the
+// uncommitted changes in the worktree.
+func (g *Git) switchBranch(repo *git.Repository, wt *git.Worktree) error {
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("head lookup failed: %w", err)
+	}
+
+	ref := plumbing.NewBranchReferenceName(g.Branch)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(ref, head.Hash())); err != nil {
+		return fmt.Errorf("branch create failed: %w", err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: ref, Keep: true}); err != nil {
+		return fmt.Errorf("checkout failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var revertsLine = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-f]{40})\.$`)
+
+var errFound = errors.New("found")
+
+// LastSync returns the most recent commit on HEAD whose subject starts with
+// prefix and that has not already been reverted.
+func (g *Git) LastSync(prefix string) (*object.Commit, error) {
+	repo, err := git.PlainOpen(g.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("repo open failed: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("head lookup failed: %w", err)
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("log failed: %w", err)
+	}
+
+	reverted := map[string]bool{}
+	var found *object.Commit
+	err = commits.ForEach(func(c *object.Commit) error {
+		for _, m := range revertsLine.FindAllStringSubmatch(c.Message, -1) {
+			reverted[m[1]] = true
+		}
+		if strings.HasPrefix(c.Message, prefix) && !reverted[c.Hash.String()] {
+			found = c
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFound) {
+		return nil, fmt.Errorf("log walk failed: %w", err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no sync commit found")
+	}
+	return found, nil
+}
+
+// Revert restores the files touched by commit to their parent's contents
+// and commits the result, pushing it when push is set. It refuses when any
+// of those files changed after commit, rather than attempting a merge,
+// except for the files in keep, which are then left as they are.
+func (g *Git) Revert(ctx context.Context, commit *object.Commit, push bool, keep ...string) error {
+	if commit.NumParents() != 1 {
+		return fmt.Errorf("commit %s has %d parents, expected 1", commit.Hash, commit.NumParents())
+	}
+
+	repo, err := git.PlainOpen(g.Dir)
+	if err != nil {
+		return fmt.Errorf("repo open failed: %w", err)
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return fmt.Errorf("parent lookup failed: %w", err)
+	}
+	before, err := parent.Tree()
+	if err != nil {
+		return fmt.Errorf("tree lookup failed: %w", err)
+	}
+	after, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("tree lookup failed: %w", err)
+	}
+
+	changes, err := object.DiffTreeContext(ctx, before, after)
+	if err != nil {
+		return fmt.Errorf("tree diff failed: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("worktree access failed: %w", err)
+	}
+
+	for _, change := range changes {
+		from, to, err := change.Files()
+		if err != nil {
+			return fmt.Errorf("change read failed: %w", err)
+		}
+
+		name := change.To.Name
+		if to == nil {
+			name = change.From.Name
+		}
+		path := filepath.Join(g.Dir, filepath.FromSlash(name))
+
+		if err := unchangedSince(path, to); err != nil {
+			if slices.Contains(keep, name) {
+				g.Log.Infof("keeping %s: %v", name, err)
+				continue
+			}
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if from == nil {
+			if _, err := wt.Remove(name); err != nil {
+				return fmt.Errorf("git rm failed: %w", err)
+			}
+			continue
+		}
+
+		contents, err := from.Contents()
+		if err != nil {
+			return fmt.Errorf("blob read failed: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("dir create failed: %w", err)
+		}
+		mode, err := from.Mode.ToOSFileMode()
+		if err != nil {
+			mode = 0644
+		}
+		if err := os.WriteFile(path, []byte(contents), mode); err != nil {
+			return fmt.Errorf("file write failed: %w", err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			return fmt.Errorf("git add failed: %w", err)
+		}
+		g.Log.Infof("restored %s", name)
+	}
+
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	message := fmt.Sprintf("Revert %q\n\nThis reverts commit %s.\n", subject, commit.Hash)
+	author, committer := g.signatures()
+	if _, err := wt.Commit(message, &git.CommitOptions{Author: author, Committer: committer}); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	if !push {
+		return nil
+	}
+	return g.push(ctx, repo)
+}
+
+// unchangedSince checks that the working copy at path still matches what
+// the commit left there (nil meaning the commit deleted it).
+func unchangedSince(path string, want *object.File) error {
+	got, err := os.ReadFile(path)
+	if want == nil {
+		if err == nil {
+			return fmt.Errorf("recreated after the sync commit")
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("file read failed: %w", err)
+	}
+
+	expected, err := want.Contents()
+	if err != nil {
+		return fmt.Errorf("blob read failed: %w", err)
+	}
+	if string(got) != expected {
+		return fmt.Errorf("changed after the sync commit, revert by hand")
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// S3 uploads pages to a bucket serving a static mirror of the site.
+// Endpoint reaches S3-compatible stores such as R2 or MinIO; requests are
+// path-style and signed with AWS Signature Version 4.
+type S3 struct {
+	Bucket string
+	// Prefix is prepended to every key, e.g. "staging/".
+	Prefix string
+	Region string
+	// Endpoint defaults to https://s3.<region>.amazonaws.com.
+	Endpoint string
+
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+
+	// Dir is the website repository the files are relative to.
+	Dir    string
+	Client *http.Client
+}
+
+// Upload puts each file under Prefix, skipping objects whose ETag shows
+// they already hold the same content, so the full set of pages can be
+// synced on every run. Paths inside dot directories, such as the state
+// file, are not part of the site and are skipped.
+func (s *S3) Upload(ctx context.Context, files []string) error {
+	for _, file := range files {
+		key := path.Clean(filepath.ToSlash(file))
+		if hidden(key) {
+			continue
+		}
+
+		body, err := os.ReadFile(filepath.Join(s.Dir, file))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		etag, err := s.head(ctx, s.Prefix+key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if sum := md5.Sum(body); etag == hex.EncodeToString(sum[:]) {
+			continue
+		}
+		if err := s.put(ctx, s.Prefix+key, body); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func hidden(key string) bool {
+	for _, part := range strings.Split(key, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// head returns the object's ETag without quotes, or "" when it doesn't
+// exist yet.
+func (s *S3) head(ctx context.Context, key string) (string, error) {
+	resp, err := s.do(ctx, "HEAD", key, nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+func (s *S3) put(ctx context.Context, key string, body []byte) error {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp, err := s.do(ctx, "PUT", key, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// do sends a signed request for key.
+func (s *S3) do(ctx context.Context, method, key string, body []byte, contentType string) (*http.Response, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.Region)
+	}
+	uri := "/" + s.Bucket + "/" + uriEncode(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(endpoint, "/")+uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("request creation failed: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, uri, body, time.Now().UTC())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 headers for a request without a query.
+func (s *S3) sign(req *http.Request, uri string, body []byte, now time.Time) {
+	payload := sha256Hex(body)
+	stamp := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		headers = append([]string{"content-type"}, headers...)
+	}
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+		headers = append(headers, "x-amz-security-token")
+	}
+
+	var canonical strings.Builder
+	canonical.WriteString(req.Method + "\n" + uri + "\n\n")
+	for _, h := range headers {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		canonical.WriteString(h + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signed := strings.Join(headers, ";")
+	canonical.WriteString("\n" + signed + "\n" + payload)
+
+	scope := day + "/" + s.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + sha256Hex([]byte(canonical.String()))
+
+	key := []byte("AWS4" + s.SecretKey)
+	for _, part := range []string{day, s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.AccessKey, scope, signed, signature))
+}
+
+// uriEncode escapes everything but unreserved characters and slashes, as
+// Signature Version 4 expects.
+func uriEncode(key string) string {
+	var b strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', strings.IndexByte("-_.~/", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package publish
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestS3Upload(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".synchandler"), 0755)
+	os.WriteFile(filepath.Join(dir, "news.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(dir, "events.html"), []byte("<p>same</p>"), 0644)
+	os.WriteFile(filepath.Join(dir, ".synchandler", "state.json"), []byte("{}"), 0644)
+
+	uploads := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			if r.URL.Path == "/mirror/staging/events.html" {
+				sum := md5.Sum([]byte("<p>same</p>"))
+				w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		uploads[r.URL.Path] = string(body)
+
+		if r.Method != "PUT" || r.Header.Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Errorf("%s %s with content type %q", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-west-2/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=") {
+			t.Errorf("Authorization = %q", auth)
+		}
+	}))
+	defer server.Close()
+
+	s := &S3{Bucket: "mirror", Prefix: "staging/", Region: "us-west-2", Endpoint: server.URL, AccessKey: "AKID", SecretKey: "secret", Dir: dir, Client: server.Client()}
+	if err := s.Upload(context.Background(), []string{"news.html", "events.html", ".synchandler/state.json"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(uploads) != 1 || uploads["/mirror/staging/news.html"] != "<html></html>" {
+		t.Errorf("uploads = %v", uploads)
+	}
+}
+
+func TestURIEncode(t *testing.T) {
+	if got := uriEncode("print/news (2).html"); got != "print/news%20%282%29.html" {
+		t.Errorf("uriEncode = %q", got)
+	}
+}
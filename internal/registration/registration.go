@@ -0,0 +1,211 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/sirupsen/logrus"
+)
+
+// Status is where a session's registration stands.
+type Status string
+
+const (
+	Open     Status = "open"
+	Waitlist Status = "waitlist"
+	Full     Status = "full"
+	Closed   Status = "closed"
+)
+
+// Session is one registrable session of a program.
+type Session struct {
+	Program string
+	Name    string
+	// Dates is the schedule as TeamUnify words it, e.g. "Jun 16 - Aug 8".
+	Dates  string
+	Status Status
+	// Spots is the number of openings left, or 0 when the page doesn't say.
+	Spots int
+	URL   string
+}
+
+// Title names the session with its program, unless the name already does.
+func (s Session) Title() string {
+	if s.Program == "" || strings.Contains(strings.ToLower(s.Name), strings.ToLower(s.Program)) {
+		return s.Name
+	}
+	return s.Program + ": " + s.Name
+}
+
+// statusWords are checked in order, so "Full - join the waitlist" reads
+// as waitlisted and "Registration not open" as closed.
+var statusWords = []struct {
+	pattern *regexp.Regexp
+	status  Status
+}{
+	{regexp.MustCompile(`(?i)\bwait[\s-]?list`), Waitlist},
+	{regexp.MustCompile(`(?i)\b(?:full|sold out|no openings)\b`), Full},
+	{regexp.MustCompile(`(?i)\b(?:closed|not open|registration ended)\b`), Closed},
+	{regexp.MustCompile(`(?i)\b(?:open|register now|spots? (?:left|available|remaining))\b`), Open},
+}
+
+var spotsLeft = regexp.MustCompile(`(?i)\b(\d+)\s+(?:spots?|openings?|spaces?)\s+(?:left|remaining|available)\b`)
+
+// ParseStatus reads a registration status out of free text.
+func ParseStatus(text string) (Status, bool) {
+	for _, w := range statusWords {
+		if w.pattern.MatchString(text) {
+			return w.status, true
+		}
+	}
+	return "", false
+}
+
+// Scraper reads session status from TeamUnify program pages.
+type Scraper struct {
+	Pages   []string
+	BaseURL string
+	// RequestTimeout caps each page fetch; zero leaves it to the context.
+	RequestTimeout time.Duration
+	Client         *http.Client
+	Log            *logrus.Logger
+}
+
+// Run fetches every page and returns their sessions in page order.
+func (s *Scraper) Run(ctx context.Context) ([]Session, error) {
+	var sessions []Session
+	for _, pageURL := range s.Pages {
+		s.Log.Infof("fetching registration page %s", pageURL)
+		doc, err := s.fetchDocument(ctx, pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pageURL, err)
+		}
+		sessions = append(sessions, s.Parse(doc)...)
+	}
+	return sessions, nil
+}
+
+// Parse reads sessions from the table rows of a program page. Each row is
+// a session: the first cell names it, the second holds its dates when
+// more cells follow, and its status comes from the row's wording. The nearest
+// heading above a table names the program. Rows without a recognizable
+// status, such as headers, are skipped.
+func (s *Scraper) Parse(doc *goquery.Document) []Session {
+	var sessions []Session
+	doc.Find("table").Each(func(i int, table *goquery.Selection) {
+		program := programName(table)
+
+		table.Find("tr").Each(func(i int, row *goquery.Selection) {
+			cells := row.ChildrenFiltered("td")
+			if cells.Length() < 2 {
+				return
+			}
+
+			text := content.Clean(strings.Join(cells.Map(func(i int, cell *goquery.Selection) string {
+				return cell.Text()
+			}), " "))
+			status, ok := ParseStatus(text)
+			if !ok {
+				return
+			}
+
+			session := Session{
+				Program: program,
+				Name:    content.Clean(cells.First().Text()),
+				Status:  status,
+			}
+			if session.Name == "" {
+				return
+			}
+			if cells.Length() > 2 {
+				if dates := content.Clean(cells.Eq(1).Text()); dates != "" {
+					if _, isStatus := ParseStatus(dates); !isStatus {
+						session.Dates = dates
+					}
+				}
+			}
+			if m := spotsLeft.FindStringSubmatch(text); m != nil {
+				session.Spots, _ = strconv.Atoi(m[1])
+			}
+			if href, ok := row.Find("a[href]").First().Attr("href"); ok {
+				session.URL = s.absoluteURL(href)
+			}
+			sessions = append(sessions, session)
+		})
+	})
+	return sessions
+}
+
+// programName is the closest heading before the table, or its caption.
+func programName(table *goquery.Selection) string {
+	if caption := content.Clean(table.Find("caption").First().Text()); caption != "" {
+		return caption
+	}
+	for sel := table; sel.Length() > 0 && !sel.Is("body"); sel = sel.Parent() {
+		if heading := sel.PrevAll().Filter("h1,h2,h3,h4").First(); heading.Length() > 0 {
+			return content.Clean(heading.Text())
+		}
+	}
+	return ""
+}
+
+func (s *Scraper) absoluteURL(ref string) string {
+	if strings.HasPrefix(ref, "/") {
+		return s.BaseURL + ref
+	}
+	return ref
+}
+
+// fetchDocument gets a page, telling login or maintenance interstitials
+// apart from a page without sessions.
+func (s *Scraper) fetchDocument(ctx context.Context, pageURL string) (*goquery.Document, error) {
+	if s.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request creation failed: %w", err)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("html parsing failed: %w", err)
+	}
+	if doc.Find("table").Length() == 0 {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,53 @@
+package registration
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParse(t *testing.T) {
+	page, err := os.ReadFile("../../fixtures/registration.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(page)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Scraper{BaseURL: "https://www.gomotionapp.com"}
+	got := s.Parse(doc)
+	want := []Session{
+		{Program: "Summer Swim Lessons", Name: "Session 1", Dates: "Jun 16 - Jun 27", Status: Full},
+		{Program: "Summer Swim Lessons", Name: "Session 2", Dates: "Jun 30 - Jul 11", Status: Waitlist, URL: "https://www.gomotionapp.com/team/cadas/page/registration/lessons-2"},
+		{Program: "Summer Swim Lessons", Name: "Session 3", Dates: "Jul 14 - Jul 25", Status: Open, Spots: 4, URL: "https://www.gomotionapp.com/team/cadas/page/registration/lessons-3"},
+		{Program: "Fall Competitive Tryouts", Name: "Tryout Day", Dates: "Aug 23", Status: Closed},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	for text, want := range map[string]Status{
+		"OPEN":                      Open,
+		"Register Now":              Open,
+		"3 spots available":         Open,
+		"Waitlist":                  Waitlist,
+		"Full - join the wait-list": Waitlist,
+		"Sold Out":                  Full,
+		"Closed":                    Closed,
+		"Registration not open":     Closed,
+	} {
+		if got, ok := ParseStatus(text); !ok || got != want {
+			t.Errorf("ParseStatus(%q) = %q, %v; want %q", text, got, ok, want)
+		}
+	}
+	if _, ok := ParseStatus("Opens June 1"); ok {
+		t.Error("ParseStatus matched a future opening")
+	}
+}
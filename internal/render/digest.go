@@ -0,0 +1,47 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/news"
+)
+
+// DigestSubject titles a newsletter built from articles.
+func DigestSubject(articles []news.Article) string {
+	if len(articles) == 1 {
+		return "DARE Aquatics: " + articles[0].Title
+	}
+	return fmt.Sprintf("DARE Aquatics: %d new announcements", len(articles))
+}
+
+// Digest renders articles as the HTML body of a newsletter.
+func Digest(articles []news.Article) string {
+	var sb strings.Builder
+	for _, article := range articles {
+		sb.WriteString(fmt.Sprintf(`
+<h2>%s</h2>
+<p><em>%s &middot; %s</em></p>
+%s
+<p><a href="%s">Read on the team site</a></p>
+<hr>
+`, html.EscapeString(article.Title), html.EscapeString(article.Author), html.EscapeString(article.Date),
+			content.Sanitize(article.Content), html.EscapeString(article.URL)))
+	}
+	return sb.String()
+}
+
+// DigestMarkdown renders the same digest for Markdown-based platforms.
+func DigestMarkdown(articles []news.Article) string {
+	var sb strings.Builder
+	for i, article := range articles {
+		if i > 0 {
+			sb.WriteString("\n\n---\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n*%s · %s*\n\n%s\n\n[Read on the team site](%s)",
+			article.Title, article.Author, article.Date, article.Markdown, article.URL))
+	}
+	return sb.String()
+}
@@ -0,0 +1,272 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/registration"
+)
+
+// Catalog holds the fixed wording of the generated pages in one language,
+// including how dates are spelled out.
+type Catalog struct {
+	Lang string
+
+	Author      string
+	Published   string
+	UnknownDate string
+	// Updated takes the date an article was edited.
+	Updated  string
+	OlderOne string
+	// OlderMany takes the number of articles.
+	OlderMany   string
+	Date        string
+	Time        string
+	MoreInfo    string
+	MoreDetails string
+	NoEvents    string
+	// RSVP wording on events; Capacity takes the number of places.
+	RSVPRequired string
+	Capacity     string
+	SignUp       string
+
+	// Registration wording. Statuses labels each registration status and
+	// SpotsMany takes the number of openings.
+	Status       string
+	Dates        string
+	Statuses     map[registration.Status]string
+	SpotsOne     string
+	SpotsMany    string
+	Register     string
+	JoinWaitlist string
+
+	// Practice schedule wording. Moved takes the date a practice's time or
+	// location changed.
+	Group        string
+	Day          string
+	PracticeTime string
+	Location     string
+	Moved        string
+	NoPractices  string
+
+	// Weekly summary wording.
+	WeeklyTitle    string
+	WeeklyEvents   string
+	WeeklyNews     string
+	WeeklyNoEvents string
+	WeeklyNoNews   string
+
+	Months [12]string
+	// The date layouts are fmt strings with indexed verbs. FullDate and
+	// ShortDate take month, day and year (ShortDate omits the year and
+	// gets a three-letter month), SameMonth the month, first day, last day
+	// and year, SameYear both months and days then the year.
+	FullDate  string
+	ShortDate string
+	SameMonth string
+	SameYear  string
+
+	// anchorSuffix keeps ids unique when several languages share a page.
+	anchorSuffix string
+}
+
+// English is the wording the site has always used.
+var English = &Catalog{
+	Lang:         "en",
+	Author:       "Author:",
+	Published:    "Published on",
+	UnknownDate:  "Unknown Date",
+	Updated:      "Updated %s",
+	OlderOne:     "See 1 older article in the news archive",
+	OlderMany:    "See %d older articles in the news archive",
+	Date:         "Date:",
+	Time:         "Time:",
+	MoreInfo:     "Click the button below for more information.",
+	MoreDetails:  "More Details",
+	NoEvents:     "No upcoming events published.",
+	RSVPRequired: "RSVP required",
+	Capacity:     "limited to %d",
+	SignUp:       "Sign Up",
+	Status:       "Status:",
+	Dates:        "Dates:",
+	Statuses: map[registration.Status]string{
+		registration.Open:     "Open",
+		registration.Waitlist: "Waitlist",
+		registration.Full:     "Full",
+		registration.Closed:   "Closed",
+	},
+	SpotsOne:       "1 spot left",
+	SpotsMany:      "%d spots left",
+	Register:       "Register",
+	JoinWaitlist:   "Join the Waitlist",
+	Group:          "Group",
+	Day:            "Day",
+	PracticeTime:   "Time",
+	Location:       "Location",
+	Moved:          "Changed %s",
+	NoPractices:    "No practices published.",
+	WeeklyTitle:    "This week at DARE Aquatics",
+	WeeklyEvents:   "Coming up",
+	WeeklyNews:     "New announcements",
+	WeeklyNoEvents: "Nothing on the calendar this week.",
+	WeeklyNoNews:   "No new announcements this week.",
+	Months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	FullDate:  "%[1]s %[2]d, %[3]d",
+	ShortDate: "%[1]s %[2]d",
+	SameMonth: "%[1]s %[2]d–%[3]d, %[4]d",
+	SameYear:  "%[1]s %[2]d – %[3]s %[4]d, %[5]d",
+}
+
+var catalogs = map[string]*Catalog{
+	"en": English,
+	"es": {
+		Lang:         "es",
+		Author:       "Autor:",
+		Published:    "Publicado el",
+		UnknownDate:  "Fecha desconocida",
+		Updated:      "Actualizado el %s",
+		OlderOne:     "Ver 1 artículo anterior en el archivo de noticias",
+		OlderMany:    "Ver %d artículos anteriores en el archivo de noticias",
+		Date:         "Fecha:",
+		Time:         "Hora:",
+		MoreInfo:     "Haga clic en el botón para más información.",
+		MoreDetails:  "Más detalles",
+		NoEvents:     "No hay eventos próximos publicados.",
+		RSVPRequired: "Se requiere confirmar asistencia",
+		Capacity:     "cupo de %d",
+		SignUp:       "Inscribirse",
+		Status:       "Estado:",
+		Dates:        "Fechas:",
+		Statuses: map[registration.Status]string{
+			registration.Open:     "Abierta",
+			registration.Waitlist: "Lista de espera",
+			registration.Full:     "Completa",
+			registration.Closed:   "Cerrada",
+		},
+		SpotsOne:       "1 lugar disponible",
+		SpotsMany:      "%d lugares disponibles",
+		Register:       "Inscribirse",
+		JoinWaitlist:   "Unirse a la lista de espera",
+		Group:          "Grupo",
+		Day:            "Día",
+		PracticeTime:   "Horario",
+		Location:       "Lugar",
+		Moved:          "Cambió el %s",
+		NoPractices:    "No hay entrenamientos publicados.",
+		WeeklyTitle:    "Esta semana en DARE Aquatics",
+		WeeklyEvents:   "Próximamente",
+		WeeklyNews:     "Anuncios nuevos",
+		WeeklyNoEvents: "No hay eventos en el calendario esta semana.",
+		WeeklyNoNews:   "No hay anuncios nuevos esta semana.",
+		Months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		FullDate:  "%[2]d de %[1]s de %[3]d",
+		ShortDate: "%[2]d %[1]s",
+		SameMonth: "%[2]d–%[3]d de %[1]s de %[4]d",
+		SameYear:  "%[2]d de %[1]s – %[4]d de %[3]s de %[5]d",
+	},
+}
+
+// LookupCatalog returns the catalog for a language code; "" selects English.
+func LookupCatalog(lang string) (*Catalog, error) {
+	if lang == "" {
+		return English, nil
+	}
+	if c, ok := catalogs[lang]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown language %q (available: %v)", lang, CatalogNames())
+}
+
+// CatalogNames lists the available languages in sorted order.
+func CatalogNames() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Localized renders a block once per catalog. A single language is returned
+// as is; several are each wrapped in an element carrying their lang
+// attribute, with anchors suffixed by language after the first.
+func Localized(cats []*Catalog, render func(*Catalog) string) string {
+	if len(cats) == 1 {
+		return render(cats[0])
+	}
+
+	var sb strings.Builder
+	for i, cat := range cats {
+		if i > 0 {
+			copied := *cat
+			copied.anchorSuffix = "-" + cat.Lang
+			cat = &copied
+		}
+		sb.WriteString(fmt.Sprintf("\n<div lang=\"%s\">%s</div>\n", cat.Lang, render(cat)))
+	}
+	return sb.String()
+}
+
+// DateRange formats the days an event covers, collapsing the parts the
+// start and end share, e.g. "January 3–5, 2025". An end at exactly midnight
+// belongs to the previous day.
+func (c *Catalog) DateRange(start, end time.Time) string {
+	end = lastDay(start, end)
+
+	sy, sm, sd := start.Date()
+	ey, em, ed := end.Date()
+	switch {
+	case !end.After(start) || sameDay(start, end):
+		return c.date(start)
+	case sy == ey && sm == em:
+		return fmt.Sprintf(c.SameMonth, c.month(sm), sd, ed, sy)
+	case sy == ey:
+		return fmt.Sprintf(c.SameYear, c.month(sm), sd, c.month(em), ed, sy)
+	}
+	return c.date(start) + " – " + c.date(end)
+}
+
+// TimeRange formats when a timed event runs using the clock layout, e.g.
+// "5:00 PM – 7:00 PM". Events spanning days include the date on each side.
+func (c *Catalog) TimeRange(start, end time.Time, clock string) string {
+	if !end.After(start) {
+		return start.Format(clock)
+	}
+	if sameDay(start, end) {
+		return start.Format(clock) + " – " + end.Format(clock)
+	}
+	return c.shortDate(start) + ", " + start.Format(clock) + " – " + c.shortDate(end) + ", " + end.Format(clock)
+}
+
+// articleDate spells out when an article was published, falling back to
+// the scraped date text when it could not be parsed.
+func (c *Catalog) articleDate(article news.Article) string {
+	switch {
+	case !article.Published.IsZero():
+		return c.date(article.Published)
+	case article.Date == "" || article.Date == news.UnknownDate:
+		return c.UnknownDate
+	}
+	return article.Date
+}
+
+func (c *Catalog) date(t time.Time) string {
+	return fmt.Sprintf(c.FullDate, c.month(t.Month()), t.Day(), t.Year())
+}
+
+func (c *Catalog) shortDate(t time.Time) string {
+	month := []rune(c.month(t.Month()))
+	if len(month) > 3 {
+		month = month[:3]
+	}
+	return fmt.Sprintf(c.ShortDate, string(month), t.Day())
+}
+
+func (c *Catalog) month(m time.Month) string {
+	return c.Months[m-1]
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/news"
+)
+
+const printDate = "Monday, January 2, 2006"
+
+// PrintPage wraps a print block in a standalone page with print styling.
+// It carries no timestamp so an unchanged page is not recommitted.
+func PrintPage(title, body string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>DARE Aquatics %[1]s</title>
+	<style>
+		body { font-family: Georgia, serif; font-size: 12pt; max-width: 7.5in; margin: 0.5in auto; color: #000; }
+		h1 { font-size: 18pt; margin-bottom: 0; }
+		.print-item { break-inside: avoid; border-top: 1px solid #999; padding-top: 6pt; }
+		.print-item h2 { font-size: 14pt; margin: 0 0 4pt; }
+		img { max-width: 100%%; }
+		a { color: #000; }
+	</style>
+</head>
+<body>
+	<h1>DARE Aquatics %[1]s</h1>
+%[2]s
+</body>
+</html>
+`, title, body)
+}
+
+// NewsPrint renders articles for the printable news page.
+func NewsPrint(articles []news.Article) string {
+	var sb strings.Builder
+	for _, article := range articles {
+		sb.WriteString(fmt.Sprintf(`
+	<div class="print-item">
+		<h2>%s</h2>
+		<p>%s &middot; %s</p>
+		<div>%s</div>
+	</div>
+`, html.EscapeString(article.Title), html.EscapeString(article.Author),
+			html.EscapeString(article.Date), content.Sanitize(article.Content)))
+	}
+	return sb.String()
+}
+
+// CalendarPrint renders upcoming events with full dates, times for timed
+// events and no buttons.
+func CalendarPrint(events []gocal.Event, now time.Time, clock string) string {
+	var sb strings.Builder
+	for _, event := range events {
+		if calendar.Past(event, now) {
+			continue
+		}
+
+		when := event.Start.Format(printDate)
+		if end := lastDay(*event.Start, *event.End).Format(printDate); end != when {
+			when += " &ndash; " + end
+		}
+		if !calendar.AllDay(event) {
+			when += ", " + English.TimeRange(*event.Start, *event.End, clock)
+		}
+		sb.WriteString(fmt.Sprintf(`
+	<div class="print-item">
+		<h2>%s</h2>
+		<p>%s</p>
+	</div>
+`, html.EscapeString(event.Summary), when))
+	}
+
+	if sb.Len() == 0 {
+		return "\n\t<p>No upcoming events published.</p>\n"
+	}
+	return sb.String()
+}
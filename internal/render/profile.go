@@ -0,0 +1,190 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"sort"
+
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/registration"
+)
+
+// Profile is the class map for one design of the site. Every profile emits
+// the same elements so content stays identical across a redesign; only the
+// classes and spacing differ.
+type Profile struct {
+	Name string
+
+	NewsItem    string
+	NewsTitle   string
+	NewsMeta    string
+	NewsContent string
+	NewsOlder   string
+	// NewsBadge marks articles edited after they were published.
+	NewsBadge string
+
+	Event       string
+	EventTitle  string
+	EventDetail string
+	Button      string
+	// RSVPBadge marks events that need a signup.
+	RSVPBadge string
+	// Statuses holds the badge class for each registration status.
+	Statuses map[registration.Status]string
+
+	// ScheduleTable styles the practice schedule, ScheduleMoved the rows
+	// of practices that recently moved and ScheduleBadge their note.
+	ScheduleTable string
+	ScheduleMoved string
+	ScheduleBadge string
+
+	// Spacers adds the <br> separators the legacy stylesheet depends on.
+	Spacers bool
+
+	Analytics Analytics
+	// Policy sanitizes article bodies again at render time; nil means
+	// content.Standard.
+	Policy *content.Policy
+}
+
+// Analytics names a data attribute added to generated links so click
+// tracking (Google Analytics, Plausible) works without post-processing.
+// Empty values leave that kind of link untouched.
+type Analytics struct {
+	Attribute string
+	// NewsLink tags links inside article bodies, ArchiveLink the link to
+	// older articles, EventButton the calendar's details button,
+	// RegisterButton the registration page's sign-up links and SignupButton
+	// the RSVP links on events.
+	NewsLink       string
+	ArchiveLink    string
+	EventButton    string
+	RegisterButton string
+	SignupButton   string
+}
+
+// WithAnalytics returns a copy of the profile that tags links.
+func (p *Profile) WithAnalytics(a Analytics) *Profile {
+	copied := *p
+	copied.Analytics = a
+	return &copied
+}
+
+// WithPolicy returns a copy of the profile that keeps what policy allows
+// in article bodies.
+func (p *Profile) WithPolicy(policy *content.Policy) *Profile {
+	copied := *p
+	copied.Policy = policy
+	return &copied
+}
+
+// attr renders the analytics attribute for value, or nothing.
+func (a Analytics) attr(value string) string {
+	if a.Attribute == "" || value == "" {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, a.Attribute, html.EscapeString(value))
+}
+
+// Legacy is the markup dareaquatics.com has always used.
+var Legacy = &Profile{
+	Name:        "legacy",
+	NewsItem:    "news-item",
+	NewsTitle:   "news-title",
+	NewsMeta:    "news-date",
+	NewsContent: "news-content",
+	NewsOlder:   "news-item news-older",
+	NewsBadge:   "news-updated",
+	Event:       "event",
+	Button:      "btn btn-primary",
+	RSVPBadge:   "event-rsvp",
+	Statuses: map[registration.Status]string{
+		registration.Open:     "status-open",
+		registration.Waitlist: "status-waitlist",
+		registration.Full:     "status-full",
+		registration.Closed:   "status-closed",
+	},
+	ScheduleTable: "schedule-table",
+	ScheduleMoved: "schedule-moved",
+	ScheduleBadge: "news-updated",
+	Spacers:       true,
+}
+
+var profiles = map[string]*Profile{
+	"legacy": Legacy,
+	"bootstrap5": {
+		Name:        "bootstrap5",
+		NewsItem:    "card mb-4 p-3",
+		NewsTitle:   "card-title h4",
+		NewsMeta:    "card-subtitle text-muted small mb-1",
+		NewsContent: "card-text mt-2",
+		NewsOlder:   "text-center mb-4",
+		NewsBadge:   "badge bg-warning text-dark",
+		Event:       "card mb-4 p-3",
+		EventTitle:  "card-title h4",
+		EventDetail: "mb-1",
+		Button:      "btn btn-primary mt-2",
+		RSVPBadge:   "badge bg-info text-dark",
+		Statuses: map[registration.Status]string{
+			registration.Open:     "badge bg-success",
+			registration.Waitlist: "badge bg-warning text-dark",
+			registration.Full:     "badge bg-danger",
+			registration.Closed:   "badge bg-secondary",
+		},
+		ScheduleTable: "table table-striped",
+		ScheduleMoved: "table-warning",
+		ScheduleBadge: "badge bg-warning text-dark",
+	},
+	"tailwind": {
+		Name:        "tailwind",
+		NewsItem:    "mb-8 rounded-lg border border-gray-200 p-6",
+		NewsTitle:   "text-2xl font-bold",
+		NewsMeta:    "text-sm text-gray-500",
+		NewsContent: "prose mt-4",
+		NewsOlder:   "mb-8 text-center",
+		NewsBadge:   "rounded bg-yellow-100 px-2 py-0.5 text-xs font-semibold text-yellow-800",
+		Event:       "mb-8 rounded-lg border border-gray-200 p-6",
+		EventTitle:  "text-xl font-bold",
+		EventDetail: "text-gray-700",
+		Button:      "mt-4 inline-block rounded bg-blue-600 px-4 py-2 text-white hover:bg-blue-700",
+		RSVPBadge:   "rounded bg-blue-100 px-2 py-0.5 text-xs font-semibold text-blue-800",
+		Statuses: map[registration.Status]string{
+			registration.Open:     "rounded bg-green-100 px-2 py-0.5 text-green-800",
+			registration.Waitlist: "rounded bg-yellow-100 px-2 py-0.5 text-yellow-800",
+			registration.Full:     "rounded bg-red-100 px-2 py-0.5 text-red-800",
+			registration.Closed:   "rounded bg-gray-100 px-2 py-0.5 text-gray-700",
+		},
+		ScheduleTable: "min-w-full divide-y divide-gray-200 text-left",
+		ScheduleMoved: "bg-yellow-50",
+		ScheduleBadge: "rounded bg-yellow-100 px-2 py-0.5 text-xs font-semibold text-yellow-800",
+	},
+}
+
+// LookupProfile returns the named profile; "" selects Legacy.
+func LookupProfile(name string) (*Profile, error) {
+	if name == "" {
+		return Legacy, nil
+	}
+	if p, ok := profiles[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown render profile %q (available: %v)", name, ProfileNames())
+}
+
+// ProfileNames lists the built-in profiles in sorted order.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// class renders a class attribute, or nothing for an empty class.
+func class(c string) string {
+	if c == "" {
+		return ""
+	}
+	return ` class="` + c + `"`
+}
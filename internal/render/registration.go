@@ -0,0 +1,58 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/dareaquatics/dare-website/internal/registration"
+)
+
+// Registration renders the block injected into registration.html, one
+// entry per session in the order TeamUnify lists them. Sessions that can
+// still be joined get a sign-up button.
+func (p *Profile) Registration(sessions []registration.Session, cat *Catalog) string {
+	var content strings.Builder
+	for _, s := range sessions {
+		status := fmt.Sprintf(`<span%s>%s</span>`, class(p.Statuses[s.Status]), cat.Statuses[s.Status])
+		if s.Status == registration.Open && s.Spots > 0 {
+			spots := fmt.Sprintf(cat.SpotsMany, s.Spots)
+			if s.Spots == 1 {
+				spots = cat.SpotsOne
+			}
+			status += " (" + spots + ")"
+		}
+
+		details := fmt.Sprintf(`<p%s><b>%s</b> %s</p>`, class(p.EventDetail), cat.Status, status)
+		if s.Dates != "" {
+			details += fmt.Sprintf(`
+		  <p%s><b>%s</b> %s</p>`, class(p.EventDetail), cat.Dates, html.EscapeString(s.Dates))
+		}
+
+		label := cat.Register
+		if s.Status == registration.Waitlist {
+			label = cat.JoinWaitlist
+		}
+		if s.URL != "" && (s.Status == registration.Open || s.Status == registration.Waitlist) {
+			details += fmt.Sprintf(`
+		  <a href="%s" target="_blank" rel="noopener noreferrer"%s%s>%s</a>`,
+				html.EscapeString(s.URL), class(p.Button), p.Analytics.attr(p.Analytics.RegisterButton), label)
+		}
+
+		trailer := ""
+		if p.Spacers {
+			trailer = "\n\t\t<br><br>"
+		}
+		content.WriteString(fmt.Sprintf(`
+		<div%s>
+		  <h2%s><strong>%s</strong></h2>
+		  %s
+		</div>%s`,
+			class(p.Event),
+			class(p.EventTitle), html.EscapeString(s.Title()),
+			details,
+			trailer,
+		))
+	}
+	return content.String()
+}
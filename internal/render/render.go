@@ -0,0 +1,201 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/news"
+)
+
+// Time-of-day layouts for timed events.
+const (
+	Clock12h = "3:04 PM"
+	Clock24h = "15:04"
+)
+
+// articleIDs are the ids News gives articles, derived from their titles;
+// repeated titles are numbered in order.
+func articleIDs(articles []news.Article) []string {
+	titles := make([]string, len(articles))
+	for i, article := range articles {
+		titles[i] = article.Title
+	}
+	return content.Slugs(titles)
+}
+
+// News renders the block injected into news.html. Each article gets an id
+// derived from its title so it can be linked to. Scraped fields are escaped
+// and bodies sanitized, since they come from outside this repository.
+func (p *Profile) News(articles []news.Article, cat *Catalog) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+
+	ids := articleIDs(articles)
+	for i, article := range articles {
+		sb.WriteString(fmt.Sprintf(`
+		<div%s id="%s">
+			<h2%s><strong>%s</strong></h2>
+			<p%s>%s %s</p>
+			<p%s>%s %s</p>%s
+			<div%s>%s</div>
+		</div>
+		`, class(p.NewsItem), ids[i]+cat.anchorSuffix,
+			class(p.NewsTitle), html.EscapeString(article.Title),
+			class(p.NewsMeta), cat.Author, html.EscapeString(article.Author),
+			class(p.NewsMeta), cat.Published, html.EscapeString(cat.articleDate(article)),
+			p.updated(article, cat),
+			class(p.NewsContent), p.body(article.Content)))
+	}
+
+	return sb.String()
+}
+
+// OlderNews renders the link placed after a news block that was capped or
+// had old articles moved to the archive.
+func (p *Profile) OlderNews(href string, count int, cat *Catalog) string {
+	label := fmt.Sprintf(cat.OlderMany, count)
+	if count == 1 {
+		label = cat.OlderOne
+	}
+
+	return fmt.Sprintf(`
+		<div%s>
+			<p%s><a href="%s"%s>%s</a></p>
+		</div>
+		`, class(p.NewsOlder), class(p.NewsMeta), html.EscapeString(href), p.Analytics.attr(p.Analytics.ArchiveLink), label)
+}
+
+// eventIDs are the ids of the events' elements on calendar.html, derived
+// from their stable ids so links keep working across syncs. Ids that slug
+// alike are numbered in order.
+func eventIDs(events []gocal.Event) []string {
+	keys := make([]string, len(events))
+	for i, event := range events {
+		keys[i] = calendar.ID(event)
+	}
+	ids := content.Slugs(keys)
+	for i := range ids {
+		ids[i] = "event-" + ids[i]
+	}
+	return ids
+}
+
+// EventAnchors maps the ID of each event on calendar.html to the id of its
+// element there. events must be the list the calendar was rendered from.
+func EventAnchors(events []gocal.Event) map[string]string {
+	ids := eventIDs(events)
+	anchors := make(map[string]string, len(events))
+	for i, event := range events {
+		if _, ok := anchors[calendar.ID(event)]; !ok {
+			anchors[calendar.ID(event)] = ids[i]
+		}
+	}
+	return anchors
+}
+
+// Calendar renders the block injected into calendar.html. Events that ended
+// before now are skipped; timed events also show their times in clock.
+// Each event's element carries the id EventAnchors gives it.
+func (p *Profile) Calendar(events []gocal.Event, rsvps calendar.RSVPs, now time.Time, clock string, cat *Catalog) string {
+	ids := eventIDs(events)
+
+	var sb strings.Builder
+	for i, event := range events {
+		// Skip past events
+		if calendar.Past(event, now) {
+			continue
+		}
+
+		when := fmt.Sprintf(`<p%s><b>%s</b> %s</p>`, class(p.EventDetail), cat.Date, cat.DateRange(*event.Start, *event.End))
+		if !calendar.AllDay(event) {
+			when += fmt.Sprintf(`
+		  <p%s><b>%s</b> %s</p>`, class(p.EventDetail), cat.Time, cat.TimeRange(*event.Start, *event.End, clock))
+		}
+		rsvp := rsvps.Of(event)
+		if rsvp.Required {
+			badge := cat.RSVPRequired
+			if rsvp.Capacity > 0 {
+				badge += " (" + fmt.Sprintf(cat.Capacity, rsvp.Capacity) + ")"
+			}
+			when += fmt.Sprintf(`
+		  <p%s><span%s>%s</span></p>`, class(p.EventDetail), class(p.RSVPBadge), badge)
+		}
+		spacer, trailer := "", ""
+		if p.Spacers {
+			spacer, trailer = "\n\t\t  <br>", "\n\t\t<br><br>"
+		}
+		sb.WriteString(fmt.Sprintf(`
+		<div%s id="%s">
+		  <h2%s><strong>%s</strong></h2>
+		  %s%s
+		  <p%s>%s</p>
+		  <a href="https://www.gomotionapp.com/team/cadas/controller/cms/admin/index?team=cadas#/calendar-team-events" 
+		     target="_blank" 
+		     rel="noopener noreferrer" 
+		    %s%s>
+		    %s
+		  </a>%s
+		</div>%s`,
+			class(p.Event), ids[i]+cat.anchorSuffix,
+			class(p.EventTitle), html.EscapeString(event.Summary),
+			when, spacer,
+			class(p.EventDetail), cat.MoreInfo,
+			class(p.Button), p.Analytics.attr(p.Analytics.EventButton), cat.MoreDetails,
+			p.signup(rsvp, cat),
+			trailer,
+		))
+	}
+
+	if sb.Len() == 0 {
+		sb.WriteString(fmt.Sprintf(`<div%s><p%s>%s</p></div>`, class(p.Event), class(p.EventDetail), cat.NoEvents))
+	}
+
+	return sb.String()
+}
+
+// signup renders the button to an event's signup form, if it has one.
+func (p *Profile) signup(rsvp calendar.RSVP, cat *Catalog) string {
+	if rsvp.SignupURL == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+		  <a href="%s" target="_blank" rel="noopener noreferrer"%s%s>%s</a>`,
+		html.EscapeString(rsvp.SignupURL), class(p.Button), p.Analytics.attr(p.Analytics.SignupButton), cat.SignUp)
+}
+
+// updated renders the badge for an article edited after publishing.
+func (p *Profile) updated(article news.Article, cat *Catalog) string {
+	if article.Updated.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(`
+			<p%s><span%s>%s</span></p>`, class(p.NewsMeta), class(p.NewsBadge), fmt.Sprintf(cat.Updated, cat.date(article.Updated)))
+}
+
+// body sanitizes an article body and tags its links for analytics.
+func (p *Profile) body(fragment string) string {
+	fragment = p.Policy.Sanitize(fragment)
+	if a := p.Analytics; a.Attribute != "" && a.NewsLink != "" {
+		fragment = content.SetLinkAttr(fragment, a.Attribute, a.NewsLink)
+	}
+	return fragment
+}
+
+// lastDay moves an end at exactly midnight back into the day it closes.
+func lastDay(start, end time.Time) time.Time {
+	if end.After(start) && end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0 && end.Nanosecond() == 0 {
+		return end.Add(-time.Nanosecond)
+	}
+	return end
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
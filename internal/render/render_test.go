@@ -0,0 +1,403 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/news"
+	"github.com/dareaquatics/dare-website/internal/registration"
+	"github.com/dareaquatics/dare-website/internal/schedule"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite testdata/*.golden.html from the current renderer output")
+
+var pacific = mustLoadLocation("America/Los_Angeles")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func event(summary string, start, end time.Time) gocal.Event {
+	return gocal.Event{Uid: summary, Summary: summary, Start: &start, End: &end}
+}
+
+// assertGolden compares got against testdata/<name>.golden.html. Run
+// `go test ./internal/render -update-golden` to accept new output.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden.html")
+
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update-golden to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s does not match renderer output (run with -update-golden to accept)\n--- got ---\n%s", path, got)
+	}
+}
+
+var sampleArticles = []news.Article{
+	{
+		Title:   "Pool Closure Notice",
+		Date:    "April 15, 2024",
+		Author:  "DARE Office",
+		Content: "<p>The pool is closed Monday.</p>",
+		URL:     "https://www.gomotionapp.com/team/cadas/page/news/article/1002",
+	},
+	{
+		Title:   "Spring Invitational Results",
+		Date:    "April 1, 2024",
+		Author:  "Coach Dana",
+		Content: `<p>Results are posted <a href="https://www.gomotionapp.com/team/cadas/page/results" target="_blank">Click here to be redirected to the link</a>.</p>`,
+		URL:     "https://www.gomotionapp.com/team/cadas/page/news/article/1001",
+	},
+}
+
+func TestNewsGolden(t *testing.T) {
+	assertGolden(t, "news", Legacy.News(sampleArticles, English))
+}
+
+func TestNewsCappedGolden(t *testing.T) {
+	assertGolden(t, "news_capped", Legacy.News(sampleArticles[:1], English)+Legacy.OlderNews("news-archive.html", 1, English))
+}
+
+func TestNewsEscapesFields(t *testing.T) {
+	article := news.Article{
+		Title:   `Results</h2><script>alert(1)</script>`,
+		Date:    "April 1, 2024",
+		Author:  `Coach "Dana" & Staff`,
+		Content: `<p onclick="steal()">Hi<script>alert(2)</script> <a href="javascript:alert(3)">link</a></p>`,
+	}
+
+	for name, got := range map[string]string{
+		"News":      Legacy.News([]news.Article{article}, English),
+		"NewsPrint": NewsPrint([]news.Article{article}),
+		"Digest":    Digest([]news.Article{article}),
+	} {
+		if strings.Contains(got, "<script") || strings.Contains(got, "onclick") || strings.Contains(got, "javascript:") {
+			t.Errorf("%s let active content through:\n%s", name, got)
+		}
+		if !strings.Contains(got, "Results&lt;/h2&gt;&lt;script&gt;") || !strings.Contains(got, "Coach &#34;Dana&#34; &amp; Staff") {
+			t.Errorf("%s did not escape the title and author:\n%s", name, got)
+		}
+	}
+}
+
+func TestAnalyticsAttributes(t *testing.T) {
+	p := Legacy.WithAnalytics(Analytics{Attribute: "data-analytics", NewsLink: "news-link", EventButton: "event-details"})
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Winter Championships", time.Date(2025, 1, 17, 0, 0, 0, 0, pacific), time.Date(2025, 1, 20, 0, 0, 0, 0, pacific)),
+	}
+
+	block := p.News(sampleArticles, English)
+	if !strings.Contains(block, `target="_blank" data-analytics="news-link">`) {
+		t.Errorf("body link not tagged:\n%s", block)
+	}
+	if older := p.OlderNews("news-archive.html", 2, English); strings.Contains(older, "data-analytics") {
+		t.Errorf("archive link tagged without a value:\n%s", older)
+	}
+	if cal := p.Calendar(events, nil, now, Clock12h, English); !strings.Contains(cal, `class="btn btn-primary" data-analytics="event-details">`) {
+		t.Errorf("event button not tagged:\n%s", cal)
+	}
+	if Legacy.Analytics.Attribute != "" {
+		t.Error("WithAnalytics modified the shared profile")
+	}
+}
+
+func TestNewsUpdatedBadge(t *testing.T) {
+	article := sampleArticles[0]
+	if got := Legacy.News([]news.Article{article}, English); strings.Contains(got, "Updated") {
+		t.Errorf("unedited article has a badge:\n%s", got)
+	}
+
+	article.Updated = time.Date(2024, 4, 18, 9, 30, 0, 0, pacific)
+	got := Legacy.News([]news.Article{article}, English)
+	if !strings.Contains(got, `<p class="news-date"><span class="news-updated">Updated April 18, 2024</span></p>`) {
+		t.Errorf("edited article has no badge:\n%s", got)
+	}
+}
+
+func TestCalendarGolden(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Past Meet", time.Date(2025, 1, 3, 0, 0, 0, 0, pacific), time.Date(2025, 1, 5, 0, 0, 0, 0, pacific)),
+		event("Winter Championships", time.Date(2025, 1, 17, 0, 0, 0, 0, pacific), time.Date(2025, 1, 20, 0, 0, 0, 0, pacific)),
+		event("Practice Schedule Change", time.Date(2025, 1, 21, 17, 0, 0, 0, pacific), time.Date(2025, 1, 21, 19, 0, 0, 0, pacific)),
+	}
+
+	assertGolden(t, "calendar", Legacy.Calendar(events, nil, now, Clock12h, English))
+}
+
+func TestCalendarRSVP(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	e := event("Clinic", time.Date(2025, 1, 17, 9, 0, 0, 0, pacific), time.Date(2025, 1, 17, 11, 0, 0, 0, pacific))
+	rsvps := calendar.RSVPs{calendar.ID(e): {Required: true, Capacity: 20, SignupURL: "https://forms.gle/abc"}}
+
+	got := Legacy.WithAnalytics(Analytics{Attribute: "data-analytics", SignupButton: "event-signup"}).Calendar([]gocal.Event{e}, rsvps, now, Clock12h, English)
+	for _, want := range []string{
+		`<span class="event-rsvp">RSVP required (limited to 20)</span>`,
+		`<a href="https://forms.gle/abc" target="_blank" rel="noopener noreferrer" class="btn btn-primary" data-analytics="event-signup">Sign Up</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar block missing %s\n%s", want, got)
+		}
+	}
+}
+
+func TestRegistrationGolden(t *testing.T) {
+	sessions := []registration.Session{
+		{Program: "Summer Swim Lessons", Name: "Session 1", Dates: "Jun 16 - Jun 27", Status: registration.Full},
+		{Program: "Summer Swim Lessons", Name: "Session 2", Status: registration.Waitlist, URL: "https://www.gomotionapp.com/team/cadas/page/registration/lessons-2"},
+		{Program: "Summer Swim Lessons", Name: "Session 3", Dates: "Jul 14 - Jul 25", Status: registration.Open, Spots: 1, URL: "https://www.gomotionapp.com/team/cadas/page/registration/lessons-3"},
+		{Program: "Tryouts", Name: "Fall Tryouts", Dates: "Aug 23", Status: registration.Closed, URL: "https://www.gomotionapp.com/team/cadas/page/registration/tryouts"},
+	}
+
+	assertGolden(t, "registration", Legacy.Registration(sessions, English))
+}
+
+func TestScheduleGolden(t *testing.T) {
+	slots := []schedule.Slot{
+		{Group: "Senior", Day: "Mon", Time: "5:30 - 7:30 PM", Location: "Azusa High"},
+		{Group: "Senior", Day: "Thu", Time: "5:00 - 6:00 AM", Location: "Memorial Park", Changed: time.Date(2025, 1, 6, 18, 0, 0, 0, pacific)},
+		{Group: "Age Group & Novice", Day: "Sat", Time: "9:00 - 10:00 AM"},
+	}
+
+	assertGolden(t, "schedule", Legacy.Schedule(slots, English)+Legacy.Schedule(nil, English))
+}
+
+func TestWeeklyGolden(t *testing.T) {
+	events := []gocal.Event{
+		event("Winter Championships", time.Date(2025, 1, 17, 0, 0, 0, 0, pacific), time.Date(2025, 1, 20, 0, 0, 0, 0, pacific)),
+		event("Practice Schedule Change", time.Date(2025, 1, 15, 17, 0, 0, 0, pacific), time.Date(2025, 1, 15, 19, 0, 0, 0, pacific)),
+	}
+
+	links := map[string]string{}
+	for url, id := range ArticleAnchors(sampleArticles) {
+		links[url] = "news.html#" + id
+	}
+	assertGolden(t, "weekly", Legacy.Weekly(sampleArticles[:1], events, links, EventAnchors(events), Clock12h, English)+Legacy.Weekly(nil, nil, nil, nil, Clock12h, English))
+}
+
+func TestEventAnchorsMatchCalendar(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Meet @ Home", time.Date(2025, 1, 17, 9, 0, 0, 0, pacific), time.Date(2025, 1, 17, 11, 0, 0, 0, pacific)),
+		event("Meet - Home", time.Date(2025, 1, 18, 9, 0, 0, 0, pacific), time.Date(2025, 1, 18, 11, 0, 0, 0, pacific)),
+	}
+
+	anchors := EventAnchors(events)
+	if anchors["Meet @ Home"] == anchors["Meet - Home"] {
+		t.Fatalf("colliding events share an anchor: %v", anchors)
+	}
+	cal := Legacy.Calendar(events, nil, now, Clock12h, English)
+	weekly := Legacy.Weekly(nil, events, nil, anchors, Clock12h, English)
+	for _, anchor := range anchors {
+		if !strings.Contains(cal, `id="`+anchor+`"`) || !strings.Contains(weekly, `calendar.html#`+anchor+`"`) {
+			t.Errorf("anchor %s missing from the calendar or weekly block", anchor)
+		}
+	}
+}
+
+func TestArticleAnchorsMatchNews(t *testing.T) {
+	articles := []news.Article{
+		{Title: "Meet Results", URL: "https://example.com/1"},
+		{Title: "Meet Results", URL: "https://example.com/2"},
+	}
+
+	anchors := ArticleAnchors(articles)
+	if anchors["https://example.com/1"] != "meet-results" || anchors["https://example.com/2"] != "meet-results-2" {
+		t.Fatalf("ArticleAnchors = %v", anchors)
+	}
+	block := Legacy.News(articles, English)
+	for _, anchor := range anchors {
+		if !strings.Contains(block, `id="`+anchor+`"`) {
+			t.Errorf("anchor %s missing from the news block", anchor)
+		}
+	}
+
+	weekly := Legacy.Weekly(articles, nil, map[string]string{"https://example.com/2": "news.html#meet-results-2"}, nil, Clock12h, English)
+	if !strings.Contains(weekly, `<li><a href="news.html#meet-results-2">Meet Results</a>`) || !strings.Contains(weekly, "<li>Meet Results – ") {
+		t.Errorf("weekly block links articles wrongly:\n%s", weekly)
+	}
+}
+
+func TestProfileGolden(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Winter Championships", time.Date(2025, 1, 17, 0, 0, 0, 0, pacific), time.Date(2025, 1, 20, 0, 0, 0, 0, pacific)),
+		event("Practice Schedule Change", time.Date(2025, 1, 21, 17, 0, 0, 0, pacific), time.Date(2025, 1, 21, 19, 0, 0, 0, pacific)),
+	}
+
+	for _, name := range ProfileNames() {
+		if name == Legacy.Name {
+			continue
+		}
+		p, err := LookupProfile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "profile_"+name, p.News(sampleArticles, English)+p.OlderNews("news-archive.html", 3, English)+p.Calendar(events, nil, now, Clock12h, English))
+	}
+
+	if _, err := LookupProfile("bootstrap3"); err == nil {
+		t.Error("LookupProfile accepted an unknown profile")
+	}
+}
+
+func TestPrintGolden(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Past Meet", time.Date(2025, 1, 3, 0, 0, 0, 0, pacific), time.Date(2025, 1, 5, 0, 0, 0, 0, pacific)),
+		event("Winter Championships", time.Date(2025, 1, 17, 0, 0, 0, 0, pacific), time.Date(2025, 1, 20, 0, 0, 0, 0, pacific)),
+		event("Practice Schedule Change", time.Date(2025, 1, 21, 17, 0, 0, 0, pacific), time.Date(2025, 1, 21, 19, 0, 0, 0, pacific)),
+	}
+
+	assertGolden(t, "news_print", PrintPage("News", NewsPrint(sampleArticles)))
+	assertGolden(t, "calendar_print", PrintPage("Calendar", CalendarPrint(events, now, Clock12h)))
+}
+
+func TestDateRange(t *testing.T) {
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, pacific)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       string
+	}{
+		{"same day", at(2025, 1, 21, 17), at(2025, 1, 21, 19), "January 21, 2025"},
+		{"all-day", at(2025, 1, 3, 0), at(2025, 1, 4, 0).Add(-time.Millisecond), "January 3, 2025"},
+		{"ends at midnight", at(2025, 1, 3, 0), at(2025, 1, 4, 0), "January 3, 2025"},
+		{"no end", at(2025, 1, 3, 9), at(2025, 1, 3, 9), "January 3, 2025"},
+		{"same month", at(2025, 1, 3, 0), at(2025, 1, 6, 0), "January 3\u20135, 2025"},
+		{"across months", at(2025, 1, 30, 8), at(2025, 2, 2, 12), "January 30 \u2013 February 2, 2025"},
+		{"across years", at(2025, 12, 30, 8), at(2026, 1, 2, 12), "December 30, 2025 \u2013 January 2, 2026"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := English.DateRange(tc.start, tc.end); got != tc.want {
+				t.Errorf("DateRange(%s, %s) = %q, want %q", tc.start, tc.end, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, pacific)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		clock      string
+		want       string
+	}{
+		{"12h", at(21, 17, 0), at(21, 19, 30), Clock12h, "5:00 PM \u2013 7:30 PM"},
+		{"24h", at(21, 17, 0), at(21, 19, 30), Clock24h, "17:00 \u2013 19:30"},
+		{"no end", at(21, 6, 0), at(21, 6, 0), Clock12h, "6:00 AM"},
+		{"overnight", at(17, 18, 0), at(19, 12, 0), Clock24h, "Jan 17, 18:00 \u2013 Jan 19, 12:00"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := English.TimeRange(tc.start, tc.end, tc.clock); got != tc.want {
+				t.Errorf("TimeRange(%s, %s) = %q, want %q", tc.start, tc.end, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSpanishDates(t *testing.T) {
+	es, err := LookupCatalog("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, pacific)
+	}
+
+	tests := []struct {
+		got, want string
+	}{
+		{es.DateRange(at(2025, 1, 21, 17), at(2025, 1, 21, 19)), "21 de enero de 2025"},
+		{es.DateRange(at(2025, 1, 3, 0), at(2025, 1, 6, 0)), "3\u20135 de enero de 2025"},
+		{es.DateRange(at(2025, 1, 30, 8), at(2025, 2, 2, 12)), "30 de enero \u2013 2 de febrero de 2025"},
+		{es.DateRange(at(2025, 12, 30, 8), at(2026, 1, 2, 12)), "30 de diciembre de 2025 \u2013 2 de enero de 2026"},
+		{es.TimeRange(at(2025, 1, 17, 18), at(2025, 1, 19, 12), Clock24h), "17 ene, 18:00 \u2013 19 ene, 12:00"},
+	}
+	for _, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("got %q, want %q", tc.got, tc.want)
+		}
+	}
+}
+
+func TestBilingualGolden(t *testing.T) {
+	es, err := LookupCatalog("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	articles := append([]news.Article{}, sampleArticles...)
+	articles[0].Published = time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	block := Localized([]*Catalog{English, es}, func(cat *Catalog) string {
+		return Legacy.News(articles[:1], cat) + Legacy.OlderNews("news-archive.html", 1, cat)
+	})
+	assertGolden(t, "news_bilingual", block)
+}
+
+func TestCalendarGoldenNoUpcoming(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := []gocal.Event{
+		event("Past Meet", time.Date(2025, 1, 3, 0, 0, 0, 0, pacific), time.Date(2025, 1, 5, 0, 0, 0, 0, pacific)),
+	}
+
+	assertGolden(t, "calendar_empty", Legacy.Calendar(events, nil, now, Clock12h, English))
+}
+
+func BenchmarkNews(b *testing.B) {
+	articles := make([]news.Article, 0, 200)
+	for len(articles) < cap(articles) {
+		articles = append(articles, sampleArticles...)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Legacy.News(articles, English)
+	}
+}
+
+func BenchmarkCalendar(b *testing.B) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, pacific)
+	events := make([]gocal.Event, 500)
+	for i := range events {
+		start := now.Add(time.Duration(i) * 6 * time.Hour)
+		events[i] = event("Practice", start, start.Add(2*time.Hour))
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Legacy.Calendar(events, nil, now, Clock12h, English)
+	}
+}
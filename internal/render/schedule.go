@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/dareaquatics/dare-website/internal/schedule"
+)
+
+// Schedule renders the block injected into schedule.html: one table row
+// per practice in the order TeamUnify lists them. Practices whose time or
+// location recently moved are highlighted with the date of the change.
+func (p *Profile) Schedule(slots []schedule.Slot, cat *Catalog) string {
+	if len(slots) == 0 {
+		return fmt.Sprintf("\n\t\t<p>%s</p>\n", cat.NoPractices)
+	}
+
+	var rows strings.Builder
+	for _, s := range slots {
+		rowClass, badge := "", ""
+		if !s.Changed.IsZero() {
+			rowClass = p.ScheduleMoved
+			badge = fmt.Sprintf(` <span%s>%s</span>`, class(p.ScheduleBadge), fmt.Sprintf(cat.Moved, cat.date(s.Changed)))
+		}
+		rows.WriteString(fmt.Sprintf(`
+		    <tr%s>
+		      <td>%s</td>
+		      <td>%s</td>
+		      <td>%s%s</td>
+		      <td>%s</td>
+		    </tr>`,
+			class(rowClass),
+			html.EscapeString(s.Group),
+			html.EscapeString(s.Day),
+			html.EscapeString(s.Time), badge,
+			html.EscapeString(s.Location),
+		))
+	}
+
+	return fmt.Sprintf(`
+		<table%s>
+		  <thead>
+		    <tr><th>%s</th><th>%s</th><th>%s</th><th>%s</th></tr>
+		  </thead>
+		  <tbody>%s
+		  </tbody>
+		</table>
+`,
+		class(p.ScheduleTable),
+		cat.Group, cat.Day, cat.PracticeTime, cat.Location,
+		rows.String(),
+	)
+}
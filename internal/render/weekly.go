@@ -0,0 +1,141 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/dareaquatics/dare-website/internal/calendar"
+	"github.com/dareaquatics/dare-website/internal/news"
+)
+
+// ArticleAnchors maps each article's URL to the id of its element on a
+// page News rendered from articles.
+func ArticleAnchors(articles []news.Article) map[string]string {
+	ids := articleIDs(articles)
+	anchors := make(map[string]string, len(articles))
+	for i, article := range articles {
+		if _, ok := anchors[article.URL]; !ok {
+			anchors[article.URL] = ids[i]
+		}
+	}
+	return anchors
+}
+
+// Weekly renders the "This week" block: the events coming up and the
+// articles posted lately, each linking to its entry on calendar.html or
+// wherever links, keyed by article URL, send it. eventAnchors come from
+// EventAnchors over the whole calendar. Articles without a link are
+// listed unlinked. The caller picks the window.
+func (p *Profile) Weekly(articles []news.Article, events []gocal.Event, links, eventAnchors map[string]string, clock string, cat *Catalog) string {
+	var upcoming strings.Builder
+	for _, event := range events {
+		upcoming.WriteString(fmt.Sprintf(`
+		    <li><a href="calendar.html#%s">%s</a> – %s</li>`,
+			eventAnchors[calendar.ID(event)], html.EscapeString(event.Summary), cat.when(event, clock)))
+	}
+
+	var posted strings.Builder
+	for _, article := range articles {
+		title := html.EscapeString(article.Title)
+		if href, ok := links[article.URL]; ok {
+			title = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), title)
+		}
+		posted.WriteString(fmt.Sprintf(`
+		    <li>%s – %s</li>`,
+			title, html.EscapeString(cat.articleDate(article))))
+	}
+
+	return fmt.Sprintf(`
+		<div%s>
+		  <h2%s><strong>%s</strong></h2>
+		  <p%s><b>%s</b></p>
+		  %s
+		  <p%s><b>%s</b></p>
+		  %s
+		</div>
+`,
+		class(p.Event),
+		class(p.EventTitle), cat.WeeklyTitle,
+		class(p.EventDetail), cat.WeeklyEvents, list(upcoming.String(), cat.WeeklyNoEvents, p.EventDetail),
+		class(p.EventDetail), cat.WeeklyNews, list(posted.String(), cat.WeeklyNoNews, p.EventDetail),
+	)
+}
+
+// list wraps rendered items in a <ul>, or says there are none.
+func list(items, none, detail string) string {
+	if items == "" {
+		return fmt.Sprintf(`<p%s>%s</p>`, class(detail), none)
+	}
+	return "<ul>" + items + "\n\t\t  </ul>"
+}
+
+// WeeklySubject titles the weekly newsletter.
+func WeeklySubject(now time.Time) string {
+	return "This week at DARE Aquatics: " + English.shortDate(now)
+}
+
+// WeeklyDigest renders the weekly summary as the HTML body of a
+// newsletter, linking back to TeamUnify since the email is read away from
+// the site.
+func WeeklyDigest(articles []news.Article, events []gocal.Event, clock string) string {
+	var sb strings.Builder
+	sb.WriteString("\n<h2>" + English.WeeklyEvents + "</h2>\n")
+	if len(events) == 0 {
+		sb.WriteString("<p>" + English.WeeklyNoEvents + "</p>\n")
+	}
+	for _, event := range events {
+		title := html.EscapeString(event.Summary)
+		if event.URL != "" {
+			title = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(event.URL), title)
+		}
+		sb.WriteString(fmt.Sprintf("<p><strong>%s</strong><br>%s</p>\n", title, English.when(event, clock)))
+	}
+
+	sb.WriteString("<hr>\n<h2>" + English.WeeklyNews + "</h2>\n")
+	if len(articles) == 0 {
+		sb.WriteString("<p>" + English.WeeklyNoNews + "</p>\n")
+	}
+	for _, article := range articles {
+		sb.WriteString(fmt.Sprintf("<p><strong><a href=\"%s\">%s</a></strong><br><em>%s</em></p>\n",
+			html.EscapeString(article.URL), html.EscapeString(article.Title), html.EscapeString(English.articleDate(article))))
+	}
+	return sb.String()
+}
+
+// WeeklyDigestMarkdown renders the same summary for Markdown-based
+// platforms.
+func WeeklyDigestMarkdown(articles []news.Article, events []gocal.Event, clock string) string {
+	var sb strings.Builder
+	sb.WriteString("## " + English.WeeklyEvents + "\n\n")
+	if len(events) == 0 {
+		sb.WriteString(English.WeeklyNoEvents + "\n")
+	}
+	for _, event := range events {
+		title := event.Summary
+		if event.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, event.URL)
+		}
+		sb.WriteString(fmt.Sprintf("- **%s** – %s\n", title, English.when(event, clock)))
+	}
+
+	sb.WriteString("\n## " + English.WeeklyNews + "\n\n")
+	if len(articles) == 0 {
+		sb.WriteString(English.WeeklyNoNews + "\n")
+	}
+	for _, article := range articles {
+		sb.WriteString(fmt.Sprintf("- [%s](%s) – *%s*\n", article.Title, article.URL, English.articleDate(article)))
+	}
+	return sb.String()
+}
+
+// when gives an event's dates, and its times unless it is all-day.
+func (c *Catalog) when(event gocal.Event, clock string) string {
+	when := c.DateRange(*event.Start, *event.End)
+	if !calendar.AllDay(event) {
+		when += ", " + c.TimeRange(*event.Start, *event.End, clock)
+	}
+	return when
+}
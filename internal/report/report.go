@@ -0,0 +1,99 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dareaquatics/dare-website/internal/state"
+)
+
+// Report summarizes a single sync run. It is handed to hooks and grows as
+// the run progresses.
+type Report struct {
+	Stage     string      `json:"stage"`
+	StartedAt time.Time   `json:"started_at"`
+	Pipelines []*Pipeline `json:"pipelines"`
+	Modified  bool        `json:"modified"`
+	Pushed    bool        `json:"pushed"`
+	// Degraded is set when a pipeline kept its published content because
+	// this run's result looked wrong.
+	Degraded bool `json:"degraded"`
+	// Destinations records how each publish destination fared.
+	Destinations []Destination `json:"destinations,omitempty"`
+	// URLs are the public addresses of the pages pushed this run, known
+	// when search.site_url is configured.
+	URLs []string `json:"urls,omitempty"`
+	// Timings covers the stages shared by every pipeline: commit, push
+	// and external publishing.
+	Timings Timings `json:"timings"`
+}
+
+// Timings maps a stage of the run to how long it took, in seconds.
+type Timings map[string]float64
+
+// Since adds the time elapsed since start to stage.
+func (t Timings) Since(stage string, start time.Time) {
+	t[stage] = math.Round((t[stage]+time.Since(start).Seconds())*1000) / 1000
+}
+
+// Pipeline is the part of the report owned by one handler (news, calendar).
+type Pipeline struct {
+	Name       string `json:"name"`
+	OutputFile string `json:"output_file"`
+	Items      int    `json:"items"`
+	Modified   bool   `json:"modified"`
+
+	// UnknownDates lists items whose publication date could not be parsed.
+	UnknownDates []string `json:"unknown_dates,omitempty"`
+	// Degraded says why the output file was left as published, e.g. "no
+	// articles found".
+	Degraded string `json:"degraded,omitempty"`
+	// Changes is set once the output file has been rewritten.
+	Changes *state.Changes `json:"changes,omitempty"`
+	// Timings covers the pipeline's own stages, such as listing_fetch,
+	// article_fetch, render and patch.
+	Timings Timings `json:"timings"`
+}
+
+// Destination is the outcome of publishing to one git remote or mirror.
+type Destination struct {
+	Name     string `json:"name"`
+	Pushed   bool   `json:"pushed"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+func New() *Report {
+	return &Report{StartedAt: time.Now().UTC(), Timings: Timings{}}
+}
+
+// Add registers a pipeline section and returns it for the handler to fill.
+func (r *Report) Add(name, outputFile string) *Pipeline {
+	p := &Pipeline{Name: name, OutputFile: outputFile, Timings: Timings{}}
+	r.Pipelines = append(r.Pipelines, p)
+	return p
+}
+
+// Handlers returns the pipeline names joined with commas.
+func (r *Report) Handlers() string {
+	names := make([]string, len(r.Pipelines))
+	for i, p := range r.Pipelines {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// OutputFiles returns every output file, in pipeline order.
+func (r *Report) OutputFiles() []string {
+	files := make([]string, len(r.Pipelines))
+	for i, p := range r.Pipelines {
+		files[i] = p.OutputFile
+	}
+	return files
+}
+
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
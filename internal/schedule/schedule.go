@@ -0,0 +1,283 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dareaquatics/dare-website/internal/content"
+	"github.com/dareaquatics/dare-website/internal/fetch"
+	"github.com/sirupsen/logrus"
+)
+
+// Slot is one practice: a group in the water on a day, at a time and
+// place.
+type Slot struct {
+	Group string
+	Day   string
+	// Time is as TeamUnify words it, e.g. "5:30 - 7:00 PM".
+	Time     string
+	Location string
+	// Changed is when a sync saw the time or location move; zero when it
+	// never did.
+	Changed time.Time
+}
+
+// Keys identifies each slot by group and day, numbering a group's second
+// and later practices on the same day, so a practice keeps its key when
+// its time moves.
+func Keys(slots []Slot) []string {
+	keys := make([]string, len(slots))
+	seen := map[string]int{}
+	for i, s := range slots {
+		key := s.Group + "/" + s.Day
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += "#" + strconv.Itoa(n)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+var weekdays = regexp.MustCompile(`(?i)^(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*\.?$`)
+
+// offDay is how a grid marks a day without practice.
+var offDay = regexp.MustCompile(`(?i)^(?:-+|–|—|off|rest|none|no practice|n/?a)?$`)
+
+var lineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// columns maps header words to the field their column holds.
+var columns = []struct {
+	pattern *regexp.Regexp
+	field   string
+}{
+	{regexp.MustCompile(`(?i)\b(?:group|squad|level|team)\b`), "group"},
+	{regexp.MustCompile(`(?i)\bdays?\b`), "day"},
+	{regexp.MustCompile(`(?i)\b(?:times?|hours)\b`), "time"},
+	{regexp.MustCompile(`(?i)\b(?:location|pool|site|facility|where)\b`), "location"},
+}
+
+// Scraper reads the practice schedule from TeamUnify pages.
+type Scraper struct {
+	Pages []string
+	// RequestTimeout caps each page fetch; zero leaves it to the context.
+	RequestTimeout time.Duration
+	Client         *http.Client
+	Log            *logrus.Logger
+}
+
+// Run fetches every page and returns their practices in page order.
+func (s *Scraper) Run(ctx context.Context) ([]Slot, error) {
+	var slots []Slot
+	for _, pageURL := range s.Pages {
+		s.Log.Infof("fetching practice schedule page %s", pageURL)
+		doc, err := s.fetchDocument(ctx, pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pageURL, err)
+		}
+		slots = append(slots, Parse(doc)...)
+	}
+	return slots, nil
+}
+
+// Parse reads practices from the tables of a schedule page. A table is
+// either a list, with a header naming the group, day, time and location
+// columns, or a grid with a row per group and a column per weekday whose
+// cells hold the time, then the location after "@" or on its own line.
+// A list without a group column takes the nearest heading above it.
+func Parse(doc *goquery.Document) []Slot {
+	var slots []Slot
+	doc.Find("table").Each(func(i int, table *goquery.Selection) {
+		rows := table.Find("tr")
+		header := cellTexts(rows.First())
+		if fields := listColumns(header); fields != nil {
+			slots = append(slots, parseList(rows.Slice(1, rows.Length()), fields, heading(table))...)
+		} else if isGrid(header) {
+			slots = append(slots, parseGrid(rows.Slice(1, rows.Length()), header)...)
+		}
+	})
+	return slots
+}
+
+// listColumns returns the field of each header cell when the header names
+// at least a day and a time column.
+func listColumns(header []string) []string {
+	fields := make([]string, len(header))
+	var day, tm bool
+	for i, text := range header {
+		for _, c := range columns {
+			if c.pattern.MatchString(text) {
+				fields[i] = c.field
+				day = day || c.field == "day"
+				tm = tm || c.field == "time"
+				break
+			}
+		}
+	}
+	if !day || !tm {
+		return nil
+	}
+	return fields
+}
+
+func isGrid(header []string) bool {
+	days := 0
+	for _, text := range header {
+		if weekdays.MatchString(text) {
+			days++
+		}
+	}
+	return days >= 2
+}
+
+func parseList(rows *goquery.Selection, fields []string, group string) []Slot {
+	var slots []Slot
+	rows.Each(func(i int, row *goquery.Selection) {
+		slot := Slot{Group: group}
+		for j, text := range cellTexts(row) {
+			if j >= len(fields) {
+				break
+			}
+			switch fields[j] {
+			case "group":
+				slot.Group = text
+			case "day":
+				slot.Day = text
+			case "time":
+				slot.Time = text
+			case "location":
+				slot.Location = text
+			}
+		}
+		if slot.Day != "" && slot.Time != "" && !offDay.MatchString(slot.Time) {
+			slots = append(slots, slot)
+		}
+	})
+	return slots
+}
+
+func parseGrid(rows *goquery.Selection, header []string) []Slot {
+	var slots []Slot
+	rows.Each(func(i int, row *goquery.Selection) {
+		cells := row.ChildrenFiltered("td,th")
+		group := content.Clean(cells.First().Text())
+		if group == "" {
+			return
+		}
+		cells.Each(func(j int, cell *goquery.Selection) {
+			if j == 0 || j >= len(header) || !weekdays.MatchString(header[j]) {
+				return
+			}
+			for _, line := range cellLines(cell) {
+				tm, location, _ := strings.Cut(line[0], "@")
+				tm, location = content.Clean(tm), content.Clean(location)
+				if location == "" && len(line) > 1 {
+					location = strings.Join(line[1:], ", ")
+				}
+				if tm != "" && !offDay.MatchString(tm) {
+					slots = append(slots, Slot{Group: group, Day: header[j], Time: tm, Location: location})
+				}
+			}
+		})
+	})
+	return slots
+}
+
+// cellLines splits a grid cell into practices, one per paragraph or list
+// item when it holds several, each split into its <br> separated lines.
+func cellLines(cell *goquery.Selection) [][]string {
+	parts := cell.Find("p,li")
+	if parts.Length() == 0 {
+		parts = cell
+	}
+
+	var practices [][]string
+	parts.Each(func(i int, part *goquery.Selection) {
+		html, _ := part.Html()
+		var lines []string
+		for _, fragment := range lineBreak.Split(html, -1) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+			if err != nil {
+				continue
+			}
+			if line := content.Clean(doc.Text()); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			practices = append(practices, lines)
+		}
+	})
+	return practices
+}
+
+func cellTexts(row *goquery.Selection) []string {
+	return row.ChildrenFiltered("td,th").Map(func(i int, cell *goquery.Selection) string {
+		return content.Clean(cell.Text())
+	})
+}
+
+// heading is the closest heading before the table, or its caption.
+func heading(table *goquery.Selection) string {
+	if caption := content.Clean(table.Find("caption").First().Text()); caption != "" {
+		return caption
+	}
+	for sel := table; sel.Length() > 0 && !sel.Is("body"); sel = sel.Parent() {
+		if h := sel.PrevAll().Filter("h1,h2,h3,h4").First(); h.Length() > 0 {
+			return content.Clean(h.Text())
+		}
+	}
+	return ""
+}
+
+// fetchDocument gets a page, telling login or maintenance interstitials
+// apart from a page without a schedule.
+func (s *Scraper) fetchDocument(ctx context.Context, pageURL string) (*goquery.Document, error) {
+	if s.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request creation failed: %w", err)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("html parsing failed: %w", err)
+	}
+	if doc.Find("table").Length() == 0 {
+		if blocked := fetch.DetectBlock(resp, body); blocked != nil {
+			return nil, blocked
+		}
+	}
+	return doc, nil
+}
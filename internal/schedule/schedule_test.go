@@ -0,0 +1,48 @@
+package schedule
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParse(t *testing.T) {
+	page, err := os.ReadFile("../../fixtures/practice-schedule.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(page)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := Parse(doc)
+	want := []Slot{
+		{Group: "Senior", Day: "Mon", Time: "5:30 - 7:30 PM", Location: "Azusa High"},
+		{Group: "Senior", Day: "Tue", Time: "5:30 - 7:30 PM", Location: "Azusa High"},
+		{Group: "Senior", Day: "Thu", Time: "5:00 - 6:00 AM", Location: "Memorial Park"},
+		{Group: "Senior", Day: "Thu", Time: "5:30 - 7:30 PM", Location: "Azusa High"},
+		{Group: "Age Group", Day: "Mon", Time: "4:30 - 5:45 PM", Location: "Memorial Park"},
+		{Group: "Age Group", Day: "Wed", Time: "4:30 - 5:45 PM", Location: "Memorial Park"},
+		{Group: "Age Group", Day: "Fri", Time: "4:30 - 5:45 PM", Location: "Memorial Park"},
+		{Group: "Novice", Day: "Saturday", Time: "9:00 - 10:00 AM", Location: "Memorial Park"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	slots := []Slot{
+		{Group: "Senior", Day: "Thu", Time: "5:00 AM"},
+		{Group: "Senior", Day: "Thu", Time: "5:30 PM"},
+		{Group: "Novice", Day: "Thu", Time: "5:30 PM"},
+	}
+	want := []string{"Senior/Thu", "Senior/Thu#2", "Novice/Thu"}
+	if got := Keys(slots); !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const DefaultEndpoint = "https://api.indexnow.org/indexnow"
+
+// IndexNow submits changed URLs to the search engines that share the
+// IndexNow protocol (Bing, Yandex, Seznam and others). The key has to be
+// served from the site, at KeyLocation or at /<key>.txt.
+type IndexNow struct {
+	Endpoint    string
+	Key         string
+	KeyLocation string
+	Client      *http.Client
+}
+
+// Submit announces urls, which must all belong to one host.
+func (n *IndexNow) Submit(ctx context.Context, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(urls[0])
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", urls[0], err)
+	}
+
+	payload, err := json.Marshal(struct {
+		Host        string   `json:"host"`
+		Key         string   `json:"key"`
+		KeyLocation string   `json:"keyLocation,omitempty"`
+		URLList     []string `json:"urlList"`
+	}{u.Host, n.Key, n.KeyLocation, urls})
+	if err != nil {
+		return fmt.Errorf("json encode failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return do(n.Client, req)
+}
+
+// PingSitemap asks a search engine to recrawl a sitemap through its ping
+// endpoint, e.g. "https://www.bing.com/ping".
+func PingSitemap(ctx context.Context, client *http.Client, endpoint, sitemap string) error {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+sep+"sitemap="+url.QueryEscape(sitemap), nil)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	return do(client, req)
+}
+
+// PageURLs maps the HTML files a sync committed to their public URLs under
+// siteURL. Other files, such as the state file, are not pages and are left
+// out; index.html maps to its directory.
+func PageURLs(siteURL string, files []string) []string {
+	base := strings.TrimRight(siteURL, "/")
+	var urls []string
+	for _, file := range files {
+		file = strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, "\\", "/")), "./")
+		if !strings.HasSuffix(file, ".html") {
+			continue
+		}
+		if path.Base(file) == "index.html" {
+			file = strings.TrimSuffix(file, "index.html")
+		}
+		urls = append(urls, base+"/"+file)
+	}
+	return urls
+}
+
+func do(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
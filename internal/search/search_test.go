@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPageURLs(t *testing.T) {
+	got := PageURLs("https://dareaquatics.com/", []string{
+		"news.html", "./calendar.html", "print/news.html", "index.html", ".synchandler/state.json", "SYNC_CHANGELOG.md",
+	})
+	want := []string{
+		"https://dareaquatics.com/news.html",
+		"https://dareaquatics.com/calendar.html",
+		"https://dareaquatics.com/print/news.html",
+		"https://dareaquatics.com/",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PageURLs = %q, want %q", got, want)
+	}
+}
+
+func TestIndexNowSubmit(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := &IndexNow{Endpoint: server.URL, Key: "k123", Client: server.Client()}
+	urls := []string{"https://dareaquatics.com/news.html", "https://dareaquatics.com/calendar.html"}
+	if err := n.Submit(context.Background(), urls); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["host"] != "dareaquatics.com" || body["key"] != "k123" {
+		t.Errorf("payload = %v", body)
+	}
+	if _, ok := body["keyLocation"]; ok {
+		t.Errorf("keyLocation sent without being configured: %v", body)
+	}
+	if list, _ := body["urlList"].([]interface{}); len(list) != 2 {
+		t.Errorf("urlList = %v", body["urlList"])
+	}
+}
+
+func TestIndexNowRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "key not valid", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := &IndexNow{Endpoint: server.URL, Key: "bad", Client: server.Client()}
+	if err := n.Submit(context.Background(), []string{"https://dareaquatics.com/news.html"}); err == nil {
+		t.Error("Submit succeeded on a 403")
+	}
+}
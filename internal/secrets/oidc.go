@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// stsEndpoint is where web identity tokens are exchanged; tests point it
+// at a local server.
+var stsEndpoint = "https://sts.amazonaws.com/"
+
+// AWSCredentials are temporary keys issued by STS.
+type AWSCredentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// AssumeRoleWithOIDC trades the GitHub Actions OIDC token for temporary
+// credentials for roleARN, so no long-lived AWS keys need to be stored.
+// The workflow needs the id-token: write permission.
+func AssumeRoleWithOIDC(ctx context.Context, client *http.Client, roleARN string) (*AWSCredentials, error) {
+	token, err := actionsToken(ctx, client, "sts.amazonaws.com")
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {KeyringService},
+		"WebIdentityToken": {token},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", stsEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("request creation failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("assume role failed: %w", err)
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("assume role response parse failed: %w", err)
+	}
+	creds := result.Credentials
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("assume role returned no credentials")
+	}
+
+	Register(creds.SecretAccessKey)
+	Register(creds.SessionToken)
+	return &AWSCredentials{AccessKey: creds.AccessKeyID, SecretKey: creds.SecretAccessKey, SessionToken: creds.SessionToken}, nil
+}
+
+// actionsToken requests an OIDC token for audience from the Actions
+// runtime.
+func actionsToken(ctx context.Context, client *http.Client, audience string) (string, error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", fmt.Errorf("no GitHub Actions OIDC token available (does the workflow have id-token: write?)")
+	}
+	Register(requestToken)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL+"&audience="+url.QueryEscape(audience), nil)
+	if err != nil {
+		return "", fmt.Errorf("request creation failed: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	body, err := do(client, req)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request failed: %w", err)
+	}
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("oidc token response parse failed: %w", err)
+	}
+	if result.Value == "" {
+		return "", fmt.Errorf("oidc token response had no token")
+	}
+	Register(result.Value)
+	return result.Value, nil
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response read failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,165 @@
+// Package secrets reads credentials from the environment, secret files or
+// the OS keyring, and keeps them out of logs.
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KeyringService is the keyring service secrets are stored under, with the
+// variable name as the account, e.g.
+// secret-tool store --label=synchandler service synchandler account PAT_TOKEN
+const KeyringService = "synchandler"
+
+// Placeholder replaces secrets in redacted text.
+const Placeholder = "[redacted]"
+
+var (
+	mu    sync.Mutex
+	found = map[string]string{}
+	known []string
+)
+
+// Get returns the secret named after its environment variable, e.g.
+// PAT_TOKEN, from the first source that has it:
+//   - the variable itself
+//   - the file named by NAME_FILE, as Docker and Kubernetes mount them
+//   - a NAME=value line in the file named by SYNC_SECRETS_FILE
+//   - the OS keyring (macOS Keychain, or the Secret Service on Linux)
+//
+// Whatever is found is redacted from log output from then on.
+func Get(name string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	if value, ok := found[name]; ok {
+		return value
+	}
+
+	value := lookup(name)
+	found[name] = value
+	register(value)
+	return value
+}
+
+func lookup(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	if path := os.Getenv(name + "_FILE"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimRight(string(data), "\r\n")
+		}
+	}
+	if path := os.Getenv("SYNC_SECRETS_FILE"); path != "" {
+		if value, ok := fromSecretsFile(path, name); ok {
+			return value
+		}
+	}
+	return fromKeyring(name)
+}
+
+// fromSecretsFile reads NAME=value lines, skipping blanks and # comments.
+// Values may be quoted.
+func fromSecretsFile(path, name string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if ok && strings.TrimSpace(key) == name {
+			value = strings.TrimSpace(value)
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+			}
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// fromKeyring asks the platform's keyring tool, if it is installed.
+func fromKeyring(name string) string {
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		args = []string{"security", "find-generic-password", "-s", KeyringService, "-a", name, "-w"}
+	case "linux":
+		args = []string{"secret-tool", "lookup", "service", KeyringService, "account", name}
+	default:
+		return ""
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\r\n")
+}
+
+// Register marks a value obtained some other way, such as temporary cloud
+// credentials, for redaction.
+func Register(value string) {
+	mu.Lock()
+	defer mu.Unlock()
+	register(value)
+}
+
+func register(value string) {
+	// Very short values would redact ordinary words
+	if len(value) < 6 {
+		return
+	}
+	for _, k := range known {
+		if k == value {
+			return
+		}
+	}
+	known = append(known, value)
+	// Longest first, so a secret containing another is replaced whole
+	sort.Slice(known, func(i, j int) bool { return len(known[i]) > len(known[j]) })
+}
+
+// Redact replaces every secret read so far in s.
+func Redact(s string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, value := range known {
+		s = strings.ReplaceAll(s, value, Placeholder)
+	}
+	return s
+}
+
+// Formatter wraps a logrus formatter and redacts its output.
+type Formatter struct {
+	logrus.Formatter
+}
+
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	out, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(Redact(string(out))), nil
+}
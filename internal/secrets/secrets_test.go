@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestGet(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "pat")
+	os.WriteFile(tokenFile, []byte("from-token-file\n"), 0600)
+	secretsFile := filepath.Join(dir, "secrets.env")
+	os.WriteFile(secretsFile, []byte("# local secrets\nTEST_QUOTED=\"from-secrets-file\"\nexport TEST_PLAIN=plain-value\n"), 0600)
+
+	t.Setenv("TEST_ENV", "from-environment")
+	t.Setenv("TEST_FILE_FILE", tokenFile)
+	t.Setenv("SYNC_SECRETS_FILE", secretsFile)
+
+	tests := map[string]string{
+		"TEST_ENV":     "from-environment",
+		"TEST_FILE":    "from-token-file",
+		"TEST_QUOTED":  "from-secrets-file",
+		"TEST_PLAIN":   "plain-value",
+		"TEST_MISSING": "",
+	}
+	for name, want := range tests {
+		if got := Get(name); got != want {
+			t.Errorf("Get(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFormatterRedacts(t *testing.T) {
+	t.Setenv("TEST_REDACTED", "ghp_0123456789abcdef")
+	Get("TEST_REDACTED")
+
+	var out bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&out)
+	log.SetFormatter(&Formatter{Formatter: &logrus.TextFormatter{DisableColors: true}})
+	log.Errorf("push failed: bad credentials ghp_0123456789abcdef")
+
+	if bytes.Contains(out.Bytes(), []byte("ghp_0123456789abcdef")) || !bytes.Contains(out.Bytes(), []byte(Placeholder)) {
+		t.Errorf("secret not redacted: %s", out.String())
+	}
+}
+
+func TestAssumeRoleWithOIDC(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.Header.Get("Authorization") != "Bearer request-token" || r.URL.Query().Get("audience") != "sts.amazonaws.com" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"value": "oidc-jwt"}`))
+		case "/sts":
+			r.ParseForm()
+			if r.Form.Get("WebIdentityToken") != "oidc-jwt" || r.Form.Get("RoleArn") != "arn:aws:iam::123456789012:role/site" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>
+<AccessKeyId>ASIAEXAMPLE</AccessKeyId><SecretAccessKey>temporary-secret</SecretAccessKey><SessionToken>temporary-session</SessionToken>
+</Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", srv.URL+"/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	defer func(endpoint string) { stsEndpoint = endpoint }(stsEndpoint)
+	stsEndpoint = srv.URL + "/sts"
+
+	creds, err := AssumeRoleWithOIDC(context.Background(), srv.Client(), "arn:aws:iam::123456789012:role/site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *creds != (AWSCredentials{AccessKey: "ASIAEXAMPLE", SecretKey: "temporary-secret", SessionToken: "temporary-session"}) {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+	if got := Redact("key temporary-secret"); got != "key "+Placeholder {
+		t.Errorf("temporary secret not redacted: %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	StartMarker = "<!-- START UNDER HERE -->"
+	EndMarker   = "<!-- END AUTOMATION SCRIPT -->"
+)
+
+// Patch replaces everything between the automation markers in path with
+// block and reports whether the file changed. The block takes on the
+// file's line endings.
+func Patch(path, block string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("file read failed: %w", err)
+	}
+
+	html := string(content)
+	updated, err := Replace(html, matchLineEndings(block, html))
+	if err != nil {
+		return false, err
+	}
+	if updated == html {
+		return false, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("file stat failed: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("file write failed: %w", err)
+	}
+	return true, nil
+}
+
+// Write replaces the whole file at path with content, creating it and its
+// directory if needed, and reports whether anything changed.
+func Write(path, content string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		content = matchLineEndings(content, string(existing))
+		if string(existing) == content {
+			return false, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("dir create failed: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("file write failed: %w", err)
+	}
+	return true, nil
+}
+
+// Replace returns html with the marker block swapped for block.
+func Replace(html, block string) (string, error) {
+	start := strings.Index(html, StartMarker)
+	end := strings.Index(html, EndMarker)
+	if start == -1 || end == -1 || end < start {
+		return "", fmt.Errorf("markers not found in html")
+	}
+
+	start += len(StartMarker)
+	return html[:start] + block + html[end:], nil
+}
+
+// Block returns the current contents between the markers, with LF line
+// endings like freshly rendered blocks.
+func Block(html string) (string, error) {
+	start := strings.Index(html, StartMarker)
+	end := strings.Index(html, EndMarker)
+	if start == -1 || end == -1 || end < start {
+		return "", fmt.Errorf("markers not found in html")
+	}
+	return strings.ReplaceAll(html[start+len(StartMarker):end], "\r\n", "\n"), nil
+}
+
+// matchLineEndings converts s to CRLF when like uses CRLF, so pages checked
+// out on Windows aren't rewritten just for their line endings.
+func matchLineEndings(s, like string) string {
+	if !strings.Contains(like, "\r\n") {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
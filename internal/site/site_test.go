@@ -0,0 +1,33 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPatchKeepsCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "news.html")
+	page := "<main>\r\n" + StartMarker + "\r\n<p>old</p>\r\n" + EndMarker + "\r\n</main>\r\n"
+	if err := os.WriteFile(path, []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modified, err := Patch(path, "\n<p>new</p>\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	want := "<main>\r\n" + StartMarker + "\r\n<p>new</p>\r\n" + EndMarker + "\r\n</main>\r\n"
+	if !modified || string(got) != want {
+		t.Fatalf("Patch wrote %q, want %q", got, want)
+	}
+
+	// the same block again is not a change
+	if modified, err := Patch(path, "\n<p>new</p>\n"); err != nil || modified {
+		t.Errorf("second Patch = %v, %v; want no change", modified, err)
+	}
+	if block, _ := Block(string(got)); block != "\n<p>new</p>\n" {
+		t.Errorf("Block = %q", block)
+	}
+}
@@ -0,0 +1,141 @@
+package state
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Item is one published article or event as of the last sync.
+type Item struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	Date  string `json:"date,omitempty"`
+	// Hash covers everything rendered for the item, so edits that keep the
+	// title still show up as updates.
+	Hash string `json:"hash"`
+	// Expires is when the item drops off the page on its own (an event
+	// ending). Expired items are not reported as removed.
+	Expires time.Time `json:"expires,omitempty"`
+	// FirstSeen is when a sync first published the item.
+	FirstSeen time.Time `json:"first_seen,omitempty"`
+	// UpdatedAt is when a sync last saw the item change upstream after it
+	// was first published.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// State records what each pipeline last published.
+type State struct {
+	Pipelines map[string][]Item `json:"pipelines"`
+	// LastSuccess is when a sync last fetched everything without errors,
+	// whether or not anything changed.
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	// Retry holds the article URLs that failed to fetch, by URL.
+	Retry map[string]Retry `json:"retry,omitempty"`
+}
+
+// Retry is an article that failed to fetch. It is fetched ahead of the
+// rest on later runs until it succeeds, leaves the listing or runs out of
+// attempts, after which it is fetched like any other article.
+type Retry struct {
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	Since     time.Time `json:"since"`
+}
+
+// Load reads the state file; a missing file is an empty state.
+func Load(path string) (*State, error) {
+	st := &State{Pipelines: map[string][]Item{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("state read failed: %w", err)
+	}
+
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("state parse failed: %w", err)
+	}
+	if st.Pipelines == nil {
+		st.Pipelines = map[string][]Item{}
+	}
+	return st, nil
+}
+
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("state encode failed: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("state dir create failed: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("state write failed: %w", err)
+	}
+	return nil
+}
+
+// Hash returns a short stable digest of the given fields.
+func Hash(fields ...string) string {
+	h := sha1.New()
+	for _, f := range fields {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Changes is the semantic diff between two syncs of one pipeline.
+type Changes struct {
+	// Initial is set when there was no previous state to compare with.
+	Initial bool   `json:"initial,omitempty"`
+	Added   []Item `json:"added,omitempty"`
+	Updated []Item `json:"updated,omitempty"`
+	Removed []Item `json:"removed,omitempty"`
+}
+
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// Diff compares the previously published items with the new ones. Items
+// that simply expired before now are not counted as removed.
+func Diff(previous, current []Item, now time.Time) Changes {
+	var changes Changes
+	if previous == nil {
+		changes.Initial = true
+	}
+
+	old := make(map[string]Item, len(previous))
+	for _, item := range previous {
+		old[item.Key] = item
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, item := range current {
+		seen[item.Key] = true
+		before, ok := old[item.Key]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, item)
+		case before.Hash != item.Hash:
+			changes.Updated = append(changes.Updated, item)
+		}
+	}
+
+	for _, item := range previous {
+		if seen[item.Key] || (!item.Expires.IsZero() && item.Expires.Before(now)) {
+			continue
+		}
+		changes.Removed = append(changes.Removed, item)
+	}
+	return changes
+}
@@ -0,0 +1,56 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func titles(items []Item) []string {
+	var out []string
+	for _, item := range items {
+		out = append(out, item.Title)
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	previous := []Item{
+		{Key: "a", Title: "Kept", Hash: "1"},
+		{Key: "b", Title: "Edited", Hash: "1"},
+		{Key: "c", Title: "Pulled", Hash: "1"},
+		{Key: "d", Title: "Finished Meet", Hash: "1", Expires: now.Add(-time.Hour)},
+		{Key: "e", Title: "Cancelled Meet", Hash: "1", Expires: now.Add(time.Hour)},
+	}
+	current := []Item{
+		{Key: "a", Title: "Kept", Hash: "1"},
+		{Key: "b", Title: "Edited", Hash: "2"},
+		{Key: "f", Title: "New", Hash: "1"},
+	}
+
+	changes := Diff(previous, current, now)
+	if changes.Initial {
+		t.Error("Initial set with previous state")
+	}
+	for _, tc := range []struct {
+		name string
+		got  []Item
+		want string
+	}{
+		{"added", changes.Added, "[New]"},
+		{"updated", changes.Updated, "[Edited]"},
+		{"removed", changes.Removed, "[Pulled Cancelled Meet]"},
+	} {
+		if got := fmt.Sprint(titles(tc.got)); got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDiffInitial(t *testing.T) {
+	changes := Diff(nil, []Item{{Key: "a", Title: "First"}}, time.Now())
+	if !changes.Initial || len(changes.Added) != 1 {
+		t.Errorf("Diff(nil, ...) = %+v, want initial with one added", changes)
+	}
+}
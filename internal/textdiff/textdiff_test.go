@@ -0,0 +1,30 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten"
+
+	got := Unified("old", "new", a, b, 1)
+	want := `--- old
++++ new
+@@ -2,3 +2,3 @@
+ two
+-three
++THREE
+ four
+@@ -9,1 +9,2 @@
+ nine
++ten
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedEqual(t *testing.T) {
+	if got := Unified("a", "b", "same", "same", 3); got != "" {
+		t.Errorf("Unified() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build ignore
+
+// Runs the registration pipeline from internal/app, for "go run
+// registrationSyncHandler.go" in its workflow like the other handlers.
+package main
+
+import (
+	"os"
+
+	"github.com/dareaquatics/dare-website/internal/app"
+)
+
+func main() {
+	app.Main(append([]string{"sync", "--only=registration"}, os.Args[1:]...))
+}
@@ -0,0 +1,15 @@
+//go:build ignore
+
+// Runs the weekly summary together with the news and calendar pipelines it
+// is built from, for "go run weeklySyncHandler.go" in its workflow.
+package main
+
+import (
+	"os"
+
+	"github.com/dareaquatics/dare-website/internal/app"
+)
+
+func main() {
+	app.Main(append([]string{"sync", "--only=news,calendar,weekly"}, os.Args[1:]...))
+}